@@ -0,0 +1,167 @@
+package isaac
+
+import (
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// AEADNonceSize is the nonce size of the AEAD returned by NewAEAD.
+	AEADNonceSize = 16
+	// AEADOverhead is the size of the authentication tag added by Seal.
+	AEADOverhead = sha256.Size
+	// aeadMinKey is the minimum key size accepted by NewAEAD.
+	aeadMinKey = 16
+	// aeadSubkey is the size of each derived subkey.
+	aeadSubkey = 32
+)
+
+// ErrOpen is returned by Open when a message fails authentication.
+var ErrOpen = errors.New("isaac: message authentication failed")
+
+// AEAD is the encrypt-then-MAC construction returned by NewAEAD. It
+// implements cipher.AEAD.
+type AEAD struct {
+	mu        sync.RWMutex // held for writing only by Destroy
+	encKey    []byte
+	macKey    []byte
+	destroyed bool
+}
+
+var _ cipher.AEAD = (*AEAD)(nil)
+
+// NewAEAD returns an authenticated cipher built from the ISAAC stream cipher
+// and HMAC-SHA256 in encrypt-then-MAC order.
+//
+// An encryption key and a MAC key of 32 bytes each are taken from the first
+// 64 bytes of ISAAC64 output seeded with key (width 64, Coreutils layout).
+// Seal encrypts the plaintext with NewCipherWithNonce(encryption key, nonce)
+// and appends HMAC-SHA256 over
+//
+//	additionalData || nonce || ciphertext || len(additionalData) || len(ciphertext)
+//
+// with both lengths as 64-bit little-endian integers. Open checks the tag in
+// constant time before decrypting. key must be between 16 and 2048 bytes;
+// each nonce must be used only once per key. The AEAD is safe for
+// concurrent use; call Destroy to zero its subkeys once done.
+func NewAEAD(key []byte) (*AEAD, error) {
+	if len(key) < aeadMinKey {
+		return nil, fmt.Errorf("%w: need at least %d bytes", ErrKeyLength, aeadMinKey)
+	}
+	c, err := NewCipher(key, WithWidth(64))
+	if err != nil {
+		return nil, err
+	}
+	defer c.Destroy()
+	keys := make([]byte, 2*aeadSubkey)
+	c.XORKeyStream(keys, keys)
+	return &AEAD{encKey: keys[:aeadSubkey], macKey: keys[aeadSubkey:]}, nil
+}
+
+// NonceSize implements cipher.AEAD.
+func (a *AEAD) NonceSize() int {
+	return AEADNonceSize
+}
+
+// Overhead implements cipher.AEAD.
+func (a *AEAD) Overhead() int {
+	return AEADOverhead
+}
+
+// Seal implements cipher.AEAD. It panics with ErrDestroyed after Destroy.
+func (a *AEAD) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != AEADNonceSize {
+		panic("isaac: incorrect nonce length given to AEAD")
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	ret, out := sliceForAppend(dst, len(plaintext)+AEADOverhead)
+	ct := out[:len(plaintext)]
+	a.xor(nonce, ct, plaintext)
+	a.tag(out[len(plaintext):len(plaintext)], nonce, ct, additionalData)
+	return ret
+}
+
+// Open implements cipher.AEAD. It panics with ErrDestroyed after Destroy.
+func (a *AEAD) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != AEADNonceSize {
+		panic("isaac: incorrect nonce length given to AEAD")
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.destroyed {
+		panic(ErrDestroyed)
+	}
+	if len(ciphertext) < AEADOverhead {
+		return nil, ErrOpen
+	}
+	ct := ciphertext[:len(ciphertext)-AEADOverhead]
+	var tag [AEADOverhead]byte
+	a.tag(tag[:0], nonce, ct, additionalData)
+	if !hmac.Equal(tag[:], ciphertext[len(ct):]) {
+		return nil, ErrOpen
+	}
+
+	ret, out := sliceForAppend(dst, len(ct))
+	a.xor(nonce, out, ct)
+	return ret, nil
+}
+
+// Destroy zeroes the subkeys. Later calls to Seal and Open panic with
+// ErrDestroyed.
+func (a *AEAD) Destroy() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	wipe(a.encKey)
+	wipe(a.macKey)
+	a.destroyed = true
+}
+
+// xor XORs src with the keystream of one message into dst, destroying the
+// message's cipher afterwards. The caller holds mu for reading.
+func (a *AEAD) xor(nonce, dst, src []byte) {
+	if a.destroyed {
+		panic(ErrDestroyed)
+	}
+	c, err := NewCipherWithNonce(a.encKey, nonce, WithWidth(64))
+	if err != nil {
+		// The key and nonce sizes are fixed and always valid.
+		panic(err)
+	}
+	defer c.Destroy()
+	c.XORKeyStream(dst, src)
+}
+
+// tag appends the authentication tag of a message to dst.
+func (a *AEAD) tag(dst, nonce, ciphertext, additionalData []byte) []byte {
+	mac := hmac.New(sha256.New, a.macKey)
+	mac.Write(additionalData)
+	mac.Write(nonce)
+	mac.Write(ciphertext)
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData)))
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(ciphertext)))
+	mac.Write(lengths[:])
+	return mac.Sum(dst)
+}
+
+// sliceForAppend extends in by n bytes, returning the whole slice and the
+// n new bytes, as the crypto/cipher implementations do.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
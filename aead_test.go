@@ -0,0 +1,85 @@
+package isaac
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAEAD(t *testing.T) {
+	a, err := NewAEAD([]byte("0123456789abcdef0123456789abcdef"))
+	require.NoError(t, err)
+	require.Equal(t, AEADNonceSize, a.NonceSize())
+	require.Equal(t, AEADOverhead, a.Overhead())
+
+	nonce := bytes.Repeat([]byte{1}, AEADNonceSize)
+	plain := []byte("secret media blob")
+	ad := []byte("header")
+
+	sealed := a.Seal([]byte("prefix"), nonce, plain, ad)
+	require.Equal(t, "prefix", string(sealed[:6]))
+	sealed = sealed[6:]
+	require.Len(t, sealed, len(plain)+AEADOverhead)
+
+	opened, err := a.Open(nil, nonce, sealed, ad)
+	require.NoError(t, err)
+	require.Equal(t, plain, opened)
+
+	// In-place sealing and opening.
+	buf := append([]byte{}, plain...)
+	inPlace := a.Seal(buf[:0], nonce, buf, ad)
+	require.Equal(t, sealed, inPlace)
+	opened, err = a.Open(inPlace[:0], nonce, inPlace, ad)
+	require.NoError(t, err)
+	require.Equal(t, plain, opened)
+}
+
+func TestAEADTamper(t *testing.T) {
+	a, err := NewAEAD(bytes.Repeat([]byte{9}, 32))
+	require.NoError(t, err)
+	nonce := make([]byte, AEADNonceSize)
+	sealed := a.Seal(nil, nonce, []byte("do not touch"), nil)
+
+	for i := range sealed {
+		tampered := append([]byte{}, sealed...)
+		tampered[i] ^= 1
+		_, err := a.Open(nil, nonce, tampered, nil)
+		require.ErrorIs(t, err, ErrOpen, "byte %d", i)
+	}
+
+	_, err = a.Open(nil, nonce, sealed, []byte("other ad"))
+	require.ErrorIs(t, err, ErrOpen)
+	otherNonce := bytes.Repeat([]byte{2}, AEADNonceSize)
+	_, err = a.Open(nil, otherNonce, sealed, nil)
+	require.ErrorIs(t, err, ErrOpen)
+	_, err = a.Open(nil, nonce, sealed[:AEADOverhead-1], nil)
+	require.ErrorIs(t, err, ErrOpen)
+
+	b, err := NewAEAD(bytes.Repeat([]byte{8}, 32))
+	require.NoError(t, err)
+	_, err = b.Open(nil, nonce, sealed, nil)
+	require.ErrorIs(t, err, ErrOpen)
+}
+
+func TestAEADErrors(t *testing.T) {
+	_, err := NewAEAD([]byte("short"))
+	require.ErrorIs(t, err, ErrKeyLength)
+
+	a, err := NewAEAD(make([]byte, 16))
+	require.NoError(t, err)
+	require.Panics(t, func() { a.Seal(nil, make([]byte, 12), nil, nil) })
+}
+
+func TestAEADDestroy(t *testing.T) {
+	a, err := NewAEAD(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	nonce := make([]byte, AEADNonceSize)
+	sealed := a.Seal(nil, nonce, []byte("secret"), nil)
+
+	a.Destroy()
+	require.Equal(t, make([]byte, aeadSubkey), a.encKey)
+	require.Equal(t, make([]byte, aeadSubkey), a.macKey)
+	require.PanicsWithValue(t, ErrDestroyed, func() { a.Seal(nil, nonce, []byte("secret"), nil) })
+	require.PanicsWithValue(t, ErrDestroyed, func() { _, _ = a.Open(nil, nonce, sealed, nil) })
+}
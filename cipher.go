@@ -0,0 +1,71 @@
+package isaac
+
+import (
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+)
+
+// ErrKeyLength is returned for an empty key or one longer than the seed.
+var ErrKeyLength = errors.New("isaac: invalid key length")
+
+// Cipher is the ISAAC stream cipher: it XORs data with the byte stream of a
+// Generator seeded with the key. It implements crypto/cipher.Stream and is
+// not safe for concurrent use.
+type Cipher struct {
+	g   Generator
+	buf [1024]byte
+}
+
+var _ cipher.Stream = (*Cipher)(nil)
+
+// NewCipher creates a cipher using key as the seed. The options select the
+// width, profile and byte order of the keystream as for NewGenerator; the
+// default is the 64-bit Coreutils layout. A key may be at most one state
+// table long, 1024 bytes for width 32 and 2048 bytes for width 64.
+func NewCipher(key []byte, opts ...Option) (*Cipher, error) {
+	if len(key) == 0 {
+		return nil, ErrKeyLength
+	}
+	g, err := NewGenerator(append(opts, WithSeed(key))...)
+	if errors.Is(err, ErrSeedLength) {
+		return nil, ErrKeyLength
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Cipher{g: g}, nil
+}
+
+// NewCipherWithNonce creates a cipher from a long-term key and a
+// per-message nonce, laid out in the seed as described on WithNonce. A key
+// must never be used twice with the same nonce: equal pairs give equal
+// keystreams. The key may be at most 512 bytes for width 32 and 1024 bytes
+// for width 64.
+func NewCipherWithNonce(key, nonce []byte, opts ...Option) (*Cipher, error) {
+	return NewCipher(key, append(opts, WithNonce(nonce))...)
+}
+
+// XORKeyStream XORs each byte in src with a byte from the keystream and
+// writes the result to dst. dst and src must overlap entirely or not at all.
+// It panics with ErrDestroyed after Destroy.
+func (c *Cipher) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("isaac: output smaller than input")
+	}
+	for len(src) > 0 {
+		n := min(len(src), len(c.buf))
+		if _, err := c.g.Read(c.buf[:n]); err != nil {
+			panic(err)
+		}
+		subtle.XORBytes(dst, src[:n], c.buf[:n])
+		dst, src = dst[n:], src[n:]
+	}
+}
+
+// Destroy zeroes the keystream state and buffer; the cipher cannot be used
+// afterwards.
+func (c *Cipher) Destroy() {
+	c.g.Destroy()
+	wipe(c.buf[:])
+}
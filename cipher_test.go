@@ -0,0 +1,87 @@
+package isaac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher(t *testing.T) {
+	key := []byte("0123456789abcdef")
+	plain := bytes.Repeat([]byte("attack at dawn "), 500)
+
+	c, err := NewCipher(key)
+	require.NoError(t, err)
+	ct := make([]byte, len(plain))
+	// Encrypt in uneven pieces; the keystream must continue across calls.
+	c.XORKeyStream(ct[:7], plain[:7])
+	c.XORKeyStream(ct[7:], plain[7:])
+
+	g, err := NewGenerator(WithSeed(key))
+	require.NoError(t, err)
+	ks := make([]byte, len(plain))
+	_, _ = g.Read(ks)
+	for i := range ks {
+		require.Equal(t, plain[i]^ks[i], ct[i])
+	}
+
+	c, err = NewCipher(key)
+	require.NoError(t, err)
+	c.XORKeyStream(ct, ct)
+	require.Equal(t, plain, ct)
+
+	require.Panics(t, func() { c.XORKeyStream(make([]byte, 1), make([]byte, 2)) })
+}
+
+func TestCipherKeyLength(t *testing.T) {
+	_, err := NewCipher(nil)
+	require.ErrorIs(t, err, ErrKeyLength)
+	_, err = NewCipher(make([]byte, Words*4+1), WithWidth(32))
+	require.ErrorIs(t, err, ErrKeyLength)
+	_, err = NewCipher(make([]byte, Words*8), WithWidth(64))
+	require.NoError(t, err)
+}
+
+func TestCipherWithNonce(t *testing.T) {
+	key := []byte("long-term key")
+	stream := func(key, nonce []byte, opts ...Option) []byte {
+		t.Helper()
+		c, err := NewCipherWithNonce(key, nonce, opts...)
+		require.NoError(t, err)
+		ks := make([]byte, 64)
+		c.XORKeyStream(ks, ks)
+		return ks
+	}
+
+	n1 := stream(key, []byte("nonce-1"))
+	require.Equal(t, n1, stream(key, []byte("nonce-1")))
+	require.NotEqual(t, n1, stream(key, []byte("nonce-2")))
+	require.NotEqual(t, n1, stream(key, []byte("nonce-1\x00")))
+	require.NotEqual(t, n1, stream(append(key, 0), []byte("nonce-1")))
+
+	// The documented layout: key, nonce and the two lengths.
+	var seed [Words]uint32
+	seed[0] = 0x04030201
+	seed[Words/2] = 0x00000009
+	seed[Words-2] = 4
+	seed[Words-1] = 1
+	s := New32()
+	s.Seed(seed)
+	var r [Words]uint32
+	s.Refill(&r)
+	got := stream([]byte{1, 2, 3, 4}, []byte{9}, WithWidth(32))
+	require.Equal(t, binary.LittleEndian.AppendUint32(nil, r[0]), got[:4])
+}
+
+func TestCipherWithNonceErrors(t *testing.T) {
+	_, err := NewCipherWithNonce([]byte("k"), nil)
+	require.ErrorIs(t, err, ErrNonceLength)
+	_, err = NewCipherWithNonce([]byte("k"), make([]byte, Words*4/2-7), WithWidth(32))
+	require.ErrorIs(t, err, ErrNonceLength)
+	_, err = NewCipherWithNonce(make([]byte, Words*8/2+1), []byte("n"))
+	require.ErrorIs(t, err, ErrKeyLength)
+	_, err = NewGenerator(WithSeed([]uint64{1}), WithNonce([]byte("n")))
+	require.ErrorIs(t, err, ErrNonce)
+}
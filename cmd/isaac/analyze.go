@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/lbbniu/isaac/stattest"
+)
+
+// runAnalyze implements "isaac analyze": it prints ent-style statistics for
+// each named file, or for stdin when no file is given.
+func runAnalyze(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		a, err := stattest.Analyze(stdin)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(stdout, a.String())
+		return err
+	}
+
+	for i, name := range fs.Args() {
+		a, err := analyzeFile(name)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		if fs.NArg() > 1 {
+			fmt.Fprintf(stdout, "%s:\n", name)
+		}
+		if _, err := io.WriteString(stdout, a.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func analyzeFile(name string) (stattest.Analysis, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return stattest.Analysis{}, err
+	}
+	defer f.Close()
+
+	a, err := stattest.Analyze(f)
+	if err != nil {
+		return a, fmt.Errorf("%s: %w", name, err)
+	}
+	return a, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"io"
+)
+
+// runBytes implements "isaac bytes": it writes --size bytes of output.
+func runBytes(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("bytes", flag.ContinueOnError)
+	var gf genFlags
+	gf.register(fs)
+	sizeFlag := fs.String("size", "1K", "number of bytes, with optional K, M, G or T suffix")
+	formatName := fs.String("format", formatRaw, "output format: hex, dec, raw or base64")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	format, err := parseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	size, err := parseSize(*sizeFlag)
+	if err != nil {
+		return err
+	}
+	g, err := gf.generator()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriterSize(stdout, 1<<16)
+	out := newByteWriter(bw, format)
+	if _, err := io.CopyN(out, g, size); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,257 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/lbbniu/isaac"
+)
+
+// Encrypted files start with a header:
+//
+//	magic    4 bytes  "ISAC"
+//	version  1 byte   headerVersion
+//	profile  1 byte   isaac.Profile of the keystream
+//	width    1 byte   32 or 64
+//	nonceLen 1 byte   length of the nonce
+//	nonce    nonceLen bytes
+//
+// The keystream combines key and nonce with isaac.NewCipherWithNonce.
+const (
+	headerMagic   = "ISAC"
+	headerVersion = 1
+	nonceSize     = 16
+)
+
+var errHeader = errors.New("not an isaac encrypted file")
+
+// header is the header of an encrypted file.
+type header struct {
+	version byte
+	profile isaac.Profile
+	width   int
+	nonce   []byte
+}
+
+func (h header) marshal() []byte {
+	b := append([]byte(headerMagic), h.version, byte(h.profile), byte(h.width), byte(len(h.nonce)))
+	return append(b, h.nonce...)
+}
+
+func readHeader(r io.Reader) (header, error) {
+	var fixed [8]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return header{}, fmt.Errorf("%w: %w", errHeader, err)
+	}
+	if !bytes.Equal(fixed[:4], []byte(headerMagic)) {
+		return header{}, errHeader
+	}
+	h := header{version: fixed[4], profile: isaac.Profile(fixed[5]), width: int(fixed[6])}
+	if h.version != headerVersion {
+		return h, fmt.Errorf("unsupported file version %d", h.version)
+	}
+	h.nonce = make([]byte, fixed[7])
+	if _, err := io.ReadFull(r, h.nonce); err != nil {
+		return h, fmt.Errorf("%w: %w", errHeader, err)
+	}
+	return h, nil
+}
+
+// keyFlags holds the mutually exclusive key sources.
+type keyFlags struct {
+	hex        string
+	passphrase string
+	file       string
+}
+
+func (k *keyFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&k.hex, "key-hex", "", "key as hex bytes")
+	fs.StringVar(&k.passphrase, "passphrase", "", "passphrase, stretched with PBKDF2-HMAC-SHA256")
+	fs.StringVar(&k.file, "key-file", "", "file holding the raw key bytes")
+}
+
+// key returns the key from the one source given. salt is used to stretch
+// passphrases.
+func (k *keyFlags) key(salt []byte) ([]byte, error) {
+	given := 0
+	for _, s := range []string{k.hex, k.passphrase, k.file} {
+		if s != "" {
+			given++
+		}
+	}
+	if given != 1 {
+		return nil, errors.New("need exactly one of --key-hex, --passphrase and --key-file")
+	}
+
+	switch {
+	case k.hex != "":
+		key, err := hex.DecodeString(strings.TrimPrefix(k.hex, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --key-hex: %w", err)
+		}
+		return key, nil
+	case k.passphrase != "":
+		return pbkdf2Key([]byte(k.passphrase), salt, pbkdf2Iterations, 32), nil
+	}
+	return os.ReadFile(k.file)
+}
+
+// ioFlags holds the input argument and output flag of the crypt commands.
+type ioFlags struct {
+	output string
+}
+
+func (f *ioFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.output, "o", "", "output file (default stdout)")
+}
+
+// open returns the input named by the single optional argument, or stdin,
+// and the output file or stdout. The output file is written under a
+// temporary name next to it. The returned function closes both and, given
+// the error of the command, renames the temporary file into place on
+// success and removes it on failure, so a failed run never truncates an
+// existing file.
+func (f *ioFlags) open(fs *flag.FlagSet, stdin io.Reader, stdout io.Writer) (io.Reader, io.Writer, func(error) error, error) {
+	if fs.NArg() > 1 {
+		return nil, nil, nil, errors.New("at most one input file")
+	}
+	var in io.Reader = stdin
+	var inFile, outFile *os.File
+	if fs.NArg() == 1 {
+		file, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		in, inFile = file, file
+	}
+	out := stdout
+	if f.output != "" {
+		file, err := os.CreateTemp(filepath.Dir(f.output), "."+filepath.Base(f.output)+".tmp*")
+		if err != nil {
+			if inFile != nil {
+				inFile.Close()
+			}
+			return nil, nil, nil, err
+		}
+		out, outFile = file, file
+	}
+	finish := func(err error) error {
+		if inFile != nil {
+			err = errors.Join(err, inFile.Close())
+		}
+		if outFile == nil {
+			return err
+		}
+		if err = errors.Join(err, outFile.Close()); err == nil {
+			err = os.Rename(outFile.Name(), f.output)
+		}
+		if err != nil {
+			os.Remove(outFile.Name())
+		}
+		return err
+	}
+	return in, out, finish, nil
+}
+
+// newStream builds the keystream for a file header and key.
+func newStream(h header, key []byte) (*isaac.Cipher, error) {
+	return isaac.NewCipherWithNonce(key, h.nonce, isaac.WithWidth(h.width), isaac.WithProfile(h.profile))
+}
+
+// runEncrypt implements "isaac encrypt".
+func runEncrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	var kf keyFlags
+	kf.register(fs)
+	var iof ioFlags
+	iof.register(fs)
+	width := fs.Int("width", 64, "word size of the ISAAC kernel, 32 or 64")
+	profileName := fs.String("profile", isaac.Coreutils.String(), "keystream profile: "+profileList())
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	profile, err := isaac.ParseProfile(*profileName)
+	if err != nil {
+		return err
+	}
+
+	h := header{version: headerVersion, profile: profile, width: *width, nonce: make([]byte, nonceSize)}
+	if _, err := rand.Read(h.nonce); err != nil {
+		return err
+	}
+	key, err := kf.key(h.nonce)
+	if err != nil {
+		return err
+	}
+	stream, err := newStream(h, key)
+	if err != nil {
+		return err
+	}
+
+	in, out, finish, err := iof.open(fs, stdin, stdout)
+	if err != nil {
+		return err
+	}
+	return finish(encrypt(out, in, h, stream))
+}
+
+func encrypt(out io.Writer, in io.Reader, h header, stream cipher.Stream) error {
+	bw := bufio.NewWriter(out)
+	if _, err := bw.Write(h.marshal()); err != nil {
+		return err
+	}
+	if _, err := io.Copy(cipher.StreamWriter{S: stream, W: bw}, in); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// runDecrypt implements "isaac decrypt".
+func runDecrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	var kf keyFlags
+	kf.register(fs)
+	var iof ioFlags
+	iof.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	in, out, finish, err := iof.open(fs, stdin, stdout)
+	if err != nil {
+		return err
+	}
+	return finish(decrypt(out, in, &kf))
+}
+
+func decrypt(out io.Writer, in io.Reader, kf *keyFlags) error {
+	br := bufio.NewReader(in)
+	h, err := readHeader(br)
+	if err != nil {
+		return err
+	}
+	key, err := kf.key(h.nonce)
+	if err != nil {
+		return err
+	}
+	stream, err := newStream(h, key)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(out)
+	if _, err := io.Copy(bw, cipher.StreamReader{S: stream, R: br}); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lbbniu/isaac"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("raw key bytes"), 0o600))
+	plain := bytes.Repeat([]byte("some plaintext "), 1000)
+
+	for _, keyArgs := range [][]string{
+		{"--key-hex", "00112233445566778899"},
+		{"--passphrase", "correct horse battery staple"},
+		{"--key-file", keyFile},
+	} {
+		var ct bytes.Buffer
+		args := append([]string{"encrypt", "--width", "32", "--profile", "jenkins"}, keyArgs...)
+		require.NoError(t, run(args, bytes.NewReader(plain), &ct))
+		require.Equal(t, len(plain)+8+nonceSize, ct.Len())
+		require.Equal(t, []byte{'I', 'S', 'A', 'C', headerVersion, byte(isaac.Jenkins), 32, nonceSize}, ct.Bytes()[:8])
+
+		var pt bytes.Buffer
+		require.NoError(t, run(append([]string{"decrypt"}, keyArgs...), bytes.NewReader(ct.Bytes()), &pt))
+		require.Equal(t, plain, pt.Bytes())
+	}
+}
+
+func TestEncryptFiles(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "plain")
+	enc := filepath.Join(dir, "plain.isaac")
+	dec := filepath.Join(dir, "plain.out")
+	require.NoError(t, os.WriteFile(in, []byte("file contents"), 0o600))
+
+	require.NoError(t, run([]string{"encrypt", "--key-hex", "ab", "-o", enc, in}, nil, nil))
+	require.NoError(t, run([]string{"decrypt", "--key-hex", "ab", "-o", dec, enc}, nil, nil))
+	got, err := os.ReadFile(dec)
+	require.NoError(t, err)
+	require.Equal(t, "file contents", string(got))
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"decrypt", "--key-hex", "cd", enc}, nil, &out))
+	require.NotEqual(t, "file contents", out.String())
+}
+
+// TestDecryptKeepsOutput checks that a failed decryption leaves an existing
+// output file alone and no temporary file behind.
+func TestDecryptKeepsOutput(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.isaac")
+	out := filepath.Join(dir, "out")
+	require.NoError(t, os.WriteFile(bad, []byte("not encrypted"), 0o600))
+	require.NoError(t, os.WriteFile(out, []byte("precious"), 0o600))
+
+	require.ErrorIs(t, run([]string{"decrypt", "--key-hex", "ab", "-o", out, bad}, nil, nil), errHeader)
+	enc := filepath.Join(dir, "good.isaac")
+	require.NoError(t, run([]string{"encrypt", "--key-hex", "ab", "-o", enc}, bytes.NewReader([]byte("x")), nil))
+	require.Error(t, run([]string{"decrypt", "--key-file", filepath.Join(dir, "missing"), "-o", out, enc}, nil, nil))
+
+	got, err := os.ReadFile(out)
+	require.NoError(t, err)
+	require.Equal(t, "precious", string(got))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+}
+
+func TestDecryptErrors(t *testing.T) {
+	var out bytes.Buffer
+	require.ErrorIs(t, run([]string{"decrypt", "--key-hex", "ab"}, bytes.NewReader([]byte("not encrypted")), &out), errHeader)
+	require.ErrorIs(t, run([]string{"decrypt", "--key-hex", "ab"}, bytes.NewReader(nil), &out), errHeader)
+	require.ErrorContains(t, run([]string{"decrypt", "--key-hex", "ab"}, bytes.NewReader([]byte("ISAC\x09\x00\x40\x00")), &out), "version")
+	require.ErrorContains(t, run([]string{"encrypt"}, bytes.NewReader(nil), &out), "exactly one")
+	require.ErrorContains(t, run([]string{"encrypt", "--key-hex", "ab", "--passphrase", "x"}, bytes.NewReader(nil), &out), "exactly one")
+}
+
+// TestPBKDF2 checks the key stretching against the widely published
+// PBKDF2-HMAC-SHA256 vectors for "password" and "salt".
+func TestPBKDF2(t *testing.T) {
+	for iter, want := range map[int]string{
+		1:    "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b",
+		2:    "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43",
+		4096: "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a",
+	} {
+		require.Equal(t, want, hex.EncodeToString(pbkdf2Key([]byte("password"), []byte("salt"), iter, 32)))
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lbbniu/isaac"
+)
+
+// genFlags holds the flags shared by commands that build a generator.
+type genFlags struct {
+	width   int
+	seedHex string
+	profile string
+}
+
+func (f *genFlags) register(fs *flag.FlagSet) {
+	fs.IntVar(&f.width, "width", 64, "word size of the ISAAC kernel, 32 or 64")
+	fs.StringVar(&f.seedHex, "seed-hex", "", "seed as hex bytes (default: random from crypto/rand)")
+	fs.StringVar(&f.profile, "profile", isaac.Coreutils.String(), "output profile: "+profileList())
+}
+
+// profileList returns the names of all profiles for flag help.
+func profileList() string {
+	var names []string
+	for _, p := range isaac.Profiles() {
+		names = append(names, p.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// generator builds the generator described by the flags.
+func (f *genFlags) generator() (isaac.Generator, error) {
+	profile, err := isaac.ParseProfile(f.profile)
+	if err != nil {
+		return nil, err
+	}
+	opts := []isaac.Option{isaac.WithWidth(f.width), isaac.WithProfile(profile)}
+	if f.seedHex == "" {
+		opts = append(opts, isaac.WithEntropy(rand.Reader))
+	} else {
+		seed, err := hex.DecodeString(strings.TrimPrefix(f.seedHex, "0x"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --seed-hex: %w", err)
+		}
+		opts = append(opts, isaac.WithSeed(seed))
+	}
+	return isaac.NewGenerator(opts...)
+}
+
+// sizeUnits maps size suffixes to multipliers.
+var sizeUnits = []struct {
+	suffix string
+	mult   int64
+}{
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+	{"T", 1 << 40},
+}
+
+// parseSize parses a byte count with an optional binary suffix, such as
+// "512", "64K", "10MiB" or "1G".
+func parseSize(s string) (int64, error) {
+	num := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(s), "B"), "I")
+	mult := int64(1)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(num, u.suffix) {
+			num, mult = strings.TrimSuffix(num, u.suffix), u.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 || n > (1<<62)/mult {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
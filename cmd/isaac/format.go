@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Output formats.
+const (
+	formatHex    = "hex"
+	formatDec    = "dec"
+	formatRaw    = "raw"
+	formatBase64 = "base64"
+)
+
+// parseFormat validates an output format name.
+func parseFormat(s string) (string, error) {
+	switch s {
+	case formatHex, formatDec, formatRaw, formatBase64:
+		return s, nil
+	case "decimal":
+		return formatDec, nil
+	case "binary":
+		return formatRaw, nil
+	}
+	return "", fmt.Errorf("unknown format %q, want hex, dec, raw or base64", s)
+}
+
+// newByteWriter returns a writer that encodes the bytes written to it onto
+// w in the given format. Close flushes the encoding and ends text output
+// with a newline.
+func newByteWriter(w io.Writer, format string) io.WriteCloser {
+	switch format {
+	case formatHex:
+		return &textWriter{Writer: hex.NewEncoder(w), w: w}
+	case formatBase64:
+		enc := base64.NewEncoder(base64.StdEncoding, w)
+		return &textWriter{Writer: enc, w: w, flush: enc.Close}
+	case formatDec:
+		return &decWriter{w: w}
+	}
+	return nopCloser{w}
+}
+
+// textWriter ends an encoded stream with a newline.
+type textWriter struct {
+	io.Writer
+	w     io.Writer
+	flush func() error
+}
+
+func (t *textWriter) Close() error {
+	if t.flush != nil {
+		if err := t.flush(); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(t.w, "\n")
+	return err
+}
+
+// decWriter prints every byte as a decimal number on its own line.
+type decWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (d *decWriter) Write(p []byte) (int, error) {
+	d.buf = d.buf[:0]
+	for _, b := range p {
+		d.buf = strconv.AppendUint(d.buf, uint64(b), 10)
+		d.buf = append(d.buf, '\n')
+	}
+	if _, err := d.w.Write(d.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (d *decWriter) Close() error {
+	return nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
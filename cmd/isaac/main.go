@@ -0,0 +1,76 @@
+// Command isaac generates ISAAC random output for fixtures and data files.
+//
+// Usage:
+//
+//	isaac words [-n count] [--width 32|64] [--seed-hex hex] [--format fmt]
+//	isaac bytes --size size [--width 32|64] [--seed-hex hex] [--format fmt]
+//	isaac stream [--width 32|64] [--profile name] [--layout coreutils|wechat]
+//	isaac analyze [file...]
+//	isaac encrypt (--key-hex hex | --passphrase text | --key-file file) [-o out] [file]
+//	isaac decrypt (--key-hex hex | --passphrase text | --key-file file) [-o out] [file]
+//	isaac wxdecrypt [-j n] [--prefix len] [--out dir] [--suffix s] [manifest]
+//
+// Without --seed-hex the generator is seeded from crypto/rand. Formats are
+// hex, dec, raw and base64.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: isaac <command> [flags]
+
+commands:
+  words     print random words
+  bytes     write random bytes
+  stream    write raw bytes to stdout until the reader goes away
+  analyze   report ent-style statistics of files or stdin
+  encrypt   encrypt a file with the ISAAC stream cipher
+  decrypt   decrypt a file written by encrypt
+  wxdecrypt decrypt the WeChat media files listed in a manifest
+
+run "isaac <command> -h" for the flags of a command`
+
+var errUsage = errors.New(usage)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "isaac:", err)
+		os.Exit(1)
+	}
+}
+
+// run executes the command line args, reading input from stdin and writing
+// output to stdout.
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "words":
+		return runWords(args[1:], stdout)
+	case "bytes":
+		return runBytes(args[1:], stdout)
+	case "stream":
+		return runStream(args[1:], stdout)
+	case "analyze":
+		return runAnalyze(args[1:], stdin, stdout)
+	case "encrypt":
+		return runEncrypt(args[1:], stdin, stdout)
+	case "decrypt":
+		return runDecrypt(args[1:], stdin, stdout)
+	case "wxdecrypt":
+		return runWxDecrypt(args[1:], stdin, stdout)
+	case "help", "-h", "--help":
+		fmt.Fprintln(stdout, usage)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q\n%w", args[0], errUsage)
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lbbniu/isaac"
+)
+
+func TestWords(t *testing.T) {
+	var r [isaac.Words]uint64
+	s := isaac.New64()
+	s.Seed([isaac.Words]uint64{1})
+	s.Refill(&r)
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"words", "-n", "3", "--seed-hex", "01", "--format", "dec"}, nil, &out))
+	require.Equal(t, fmt.Sprintf("%d\n%d\n%d\n", r[0], r[1], r[2]), out.String())
+
+	out.Reset()
+	require.NoError(t, run([]string{"words", "-n", "1", "--seed-hex", "01"}, nil, &out))
+	require.Equal(t, fmt.Sprintf("%016x\n", r[0]), out.String())
+}
+
+func TestBytes(t *testing.T) {
+	var raw bytes.Buffer
+	require.NoError(t, run([]string{"bytes", "--size", "3K", "--seed-hex", "0xab", "--width", "32"}, nil, &raw))
+	require.Equal(t, 3<<10, raw.Len())
+
+	g, err := isaac.NewGenerator(isaac.WithWidth(32), isaac.WithSeed([]byte{0xab}))
+	require.NoError(t, err)
+	want := make([]byte, 3<<10)
+	_, _ = g.Read(want)
+	require.Equal(t, want, raw.Bytes())
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"bytes", "--size", "16", "--seed-hex", "ab", "--width", "32", "--format", "hex"}, nil, &out))
+	require.Equal(t, hex.EncodeToString(want[:16])+"\n", out.String())
+
+	out.Reset()
+	require.NoError(t, run([]string{"bytes", "--size", "16", "--seed-hex", "ab", "--width", "32", "--format", "base64"}, nil, &out))
+	require.Equal(t, base64.StdEncoding.EncodeToString(want[:16])+"\n", out.String())
+}
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{
+		"0":     0,
+		"512":   512,
+		"64K":   64 << 10,
+		"10MiB": 10 << 20,
+		"1G":    1 << 30,
+		"2tb":   2 << 40,
+	} {
+		got, err := parseSize(in)
+		require.NoError(t, err, in)
+		require.Equal(t, want, got, in)
+	}
+	for _, in := range []string{"", "-1", "1X", "K"} {
+		_, err := parseSize(in)
+		require.Error(t, err, in)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	var out bytes.Buffer
+	require.ErrorIs(t, run(nil, nil, &out), errUsage)
+	require.ErrorContains(t, run([]string{"nope"}, nil, &out), "unknown command")
+	require.ErrorContains(t, run([]string{"words", "--format", "oct"}, nil, &out), "unknown format")
+	require.ErrorContains(t, run([]string{"words", "--seed-hex", "xyz"}, nil, &out), "seed-hex")
+}
+
+func TestStream(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"stream", "--size", "4K", "--seed-hex", "01", "--layout", "wechat"}, nil, &out))
+
+	g, err := isaac.NewGenerator(isaac.WithSeed([]byte{1}), isaac.WithProfile(isaac.WeChat))
+	require.NoError(t, err)
+	want := make([]byte, 4<<10)
+	_, _ = g.Read(want)
+	require.Equal(t, want, out.Bytes())
+
+	require.ErrorContains(t, run([]string{"stream", "--layout", "wechat", "--profile", "jenkins"}, nil, &out), "conflicts")
+	require.ErrorContains(t, run([]string{"stream", "--layout", "rc4"}, nil, &out), "unknown layout")
+}
+
+// TestStreamClosedPipe checks that an infinite stream ends cleanly when the
+// reader goes away.
+func TestStreamClosedPipe(t *testing.T) {
+	require.NoError(t, run([]string{"stream"}, nil, closedPipe{}))
+}
+
+type closedPipe struct{}
+
+func (closedPipe) Write([]byte) (int, error) {
+	return 0, syscall.EPIPE
+}
+
+func TestAnalyze(t *testing.T) {
+	var data bytes.Buffer
+	require.NoError(t, run([]string{"bytes", "--size", "64K", "--seed-hex", "01"}, nil, &data))
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"analyze"}, bytes.NewReader(data.Bytes()), &out))
+	require.Contains(t, out.String(), "of this 65536 byte file")
+
+	name := filepath.Join(t.TempDir(), "data.bin")
+	require.NoError(t, os.WriteFile(name, data.Bytes(), 0o600))
+	out.Reset()
+	require.NoError(t, run([]string{"analyze", name, name}, nil, &out))
+	require.Contains(t, out.String(), name+":\n")
+
+	require.Error(t, run([]string{"analyze", filepath.Join(t.TempDir(), "missing")}, nil, &out))
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os/signal"
+	"syscall"
+
+	"github.com/lbbniu/isaac"
+)
+
+// layouts maps --layout names to the profile providing that byte layout.
+var layouts = map[string]isaac.Profile{
+	"coreutils": isaac.Coreutils,
+	"wechat":    isaac.WeChat,
+}
+
+// runStream implements "isaac stream": it writes raw generator bytes to
+// stdout, forever unless --size is given, for piping into test batteries
+// such as PractRand (RNG_test stdin32/stdin64), Dieharder (-g 200) or
+// TestU01. The bytes are exactly what the generator's Read returns; for the
+// coreutils layout that is the words in block order, little-endian, which
+// is what the stdin32/stdin64 readers expect on x86.
+func runStream(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("stream", flag.ContinueOnError)
+	var gf genFlags
+	gf.register(fs)
+	layout := fs.String("layout", "", "byte layout: coreutils or wechat, shorthand for --profile")
+	sizeFlag := fs.String("size", "0", "stop after this many bytes, 0 for an infinite stream")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *layout != "" {
+		p, ok := layouts[*layout]
+		if !ok {
+			return fmt.Errorf("unknown layout %q, want coreutils or wechat", *layout)
+		}
+		if flagSet(fs, "profile") && gf.profile != p.String() {
+			return fmt.Errorf("--layout %s conflicts with --profile %s", *layout, gf.profile)
+		}
+		gf.profile = p.String()
+	}
+	size, err := parseSize(*sizeFlag)
+	if err != nil {
+		return err
+	}
+	g, err := gf.generator()
+	if err != nil {
+		return err
+	}
+
+	// Without this the runtime kills the process with SIGPIPE when the
+	// test battery closes stdout, before the EPIPE below can be seen.
+	signal.Ignore(syscall.SIGPIPE)
+
+	r := io.Reader(g)
+	if size > 0 {
+		r = io.LimitReader(g, size)
+	}
+	_, err = io.CopyBuffer(stdout, r, make([]byte, 1<<16))
+	if errors.Is(err, syscall.EPIPE) {
+		// The test battery closed its end of the pipe.
+		return nil
+	}
+	return err
+}
+
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/lbbniu/isaac"
+)
+
+// runWords implements "isaac words": it prints -n words, one per line for
+// the text formats, or their bytes for raw and base64.
+func runWords(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("words", flag.ContinueOnError)
+	var gf genFlags
+	gf.register(fs)
+	n := fs.Int("n", 10, "number of words")
+	formatName := fs.String("format", formatHex, "output format: hex, dec, raw or base64")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	format, err := parseFormat(*formatName)
+	if err != nil {
+		return err
+	}
+	if *n < 0 {
+		return fmt.Errorf("invalid word count %d", *n)
+	}
+	g, err := gf.generator()
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(stdout)
+	size := g.Width() / 8
+	switch format {
+	case formatHex, formatDec:
+		var buf []byte
+		for range *n {
+			buf = buf[:0]
+			w := nextWord(g)
+			if format == formatHex {
+				buf = fmt.Appendf(buf, "%0*x\n", size*2, w)
+			} else {
+				buf = append(strconv.AppendUint(buf, w, 10), '\n')
+			}
+			if _, err := bw.Write(buf); err != nil {
+				return err
+			}
+		}
+	default:
+		// Raw words are the generator's byte stream, laid out by its profile.
+		out := newByteWriter(bw, format)
+		if _, err := io.CopyN(out, g, int64(*n*size)); err != nil {
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// nextWord returns the next word of the generator's width.
+func nextWord(g isaac.Generator) uint64 {
+	if g.Width() == 32 {
+		return uint64(g.Uint32())
+	}
+	return g.Uint64()
+}
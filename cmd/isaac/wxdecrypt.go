@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/lbbniu/isaac"
+)
+
+// headLen is the least number of bytes read into memory and checked for a
+// file signature, so short --prefix values still cover the magic.
+const headLen = 64
+
+// wxJob is one manifest entry.
+type wxJob struct {
+	path string
+	key  uint64
+}
+
+// wxResult is the outcome of decrypting one file.
+type wxResult struct {
+	job    wxJob
+	out    string
+	format string
+	err    error
+}
+
+// parseManifest reads manifest entries, one per line, either as CSV
+// ("path,key") or as JSON objects ({"path": ..., "key": ...}). Keys are
+// decimal or 0x-prefixed hex; JSON keys may be numbers or strings. Blank
+// lines, lines starting with # and a "path,key" header are skipped.
+func parseManifest(r io.Reader) ([]wxJob, error) {
+	var jobs []wxJob
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var path, key string
+		if strings.HasPrefix(text, "{") {
+			var entry struct {
+				Path string          `json:"path"`
+				Key  json.RawMessage `json:"key"`
+			}
+			if err := json.Unmarshal([]byte(text), &entry); err != nil {
+				return nil, fmt.Errorf("manifest line %d: %w", line, err)
+			}
+			path, key = entry.Path, strings.Trim(string(entry.Key), `"`)
+		} else {
+			rec, err := csv.NewReader(strings.NewReader(text)).Read()
+			if err != nil {
+				return nil, fmt.Errorf("manifest line %d: %w", line, err)
+			}
+			if len(rec) != 2 {
+				return nil, fmt.Errorf("manifest line %d: want path,key", line)
+			}
+			if len(jobs) == 0 && strings.EqualFold(rec[0], "path") && strings.EqualFold(rec[1], "key") {
+				continue
+			}
+			path, key = strings.TrimSpace(rec[0]), strings.TrimSpace(rec[1])
+		}
+		if path == "" {
+			return nil, fmt.Errorf("manifest line %d: missing path", line)
+		}
+		k, err := strconv.ParseUint(key, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("manifest line %d: invalid key %q", line, key)
+		}
+		jobs = append(jobs, wxJob{path: path, key: k})
+	}
+	return jobs, sc.Err()
+}
+
+// wxDecrypter holds the settings shared by all workers.
+type wxDecrypter struct {
+	prefix   int
+	outDir   string
+	suffix   string
+	noVerify bool
+}
+
+// outPath returns where the decrypted copy of path is written.
+func (d *wxDecrypter) outPath(path string) string {
+	dir := filepath.Dir(path)
+	if d.outDir != "" {
+		dir = d.outDir
+	}
+	return filepath.Join(dir, filepath.Base(path)+d.suffix)
+}
+
+// wxWorker decrypts files one at a time with its own keystream, so workers
+// never share generator state.
+type wxWorker struct {
+	d    *wxDecrypter
+	ks   isaac.WxKeystream
+	head []byte
+}
+
+func (w *wxWorker) decrypt(job wxJob) wxResult {
+	res := wxResult{job: job, out: w.d.outPath(job.path)}
+	in, err := os.Open(job.path)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	defer in.Close()
+
+	// The head holds the whole encrypted prefix: decrypt and check it
+	// before creating the output, then copy the plaintext rest.
+	n, err := io.ReadFull(in, w.head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		res.err = err
+		return res
+	}
+	head := w.head[:n]
+	enc := head[:min(n, w.d.prefix)]
+	w.ks.Reset(job.key)
+	w.ks.XORKeyStream(enc, enc)
+	res.format, err = isaac.DetectFormat(head)
+	if err != nil && !w.d.noVerify {
+		res.err = err
+		return res
+	}
+
+	// Write through a temporary file so a failed run never leaves a
+	// truncated output or touches a file that was already there.
+	out, err := os.CreateTemp(filepath.Dir(res.out), "."+filepath.Base(res.out)+".tmp*")
+	if err != nil {
+		res.err = err
+		return res
+	}
+	_, err = out.Write(head)
+	if err == nil && n == len(w.head) {
+		_, err = io.Copy(out, in)
+	}
+	if err = errors.Join(err, out.Close()); err == nil {
+		err = os.Rename(out.Name(), res.out)
+	}
+	if err != nil {
+		os.Remove(out.Name())
+		res.err = err
+	}
+	return res
+}
+
+// checkOutputs rejects manifests where an output path would overwrite an
+// input or the output of another entry, as happens with --out when files
+// in different directories share a name.
+func (d *wxDecrypter) checkOutputs(jobs []wxJob) error {
+	inputs := make(map[string]string, len(jobs))
+	for _, job := range jobs {
+		inputs[abs(job.path)] = job.path
+	}
+	outputs := make(map[string]string, len(jobs))
+	for _, job := range jobs {
+		out := abs(d.outPath(job.path))
+		if in, ok := inputs[out]; ok {
+			return fmt.Errorf("output of %s would overwrite the input %s", job.path, in)
+		}
+		if prev, ok := outputs[out]; ok {
+			return fmt.Errorf("%s and %s would both be written to %s", prev, job.path, d.outPath(job.path))
+		}
+		outputs[out] = job.path
+	}
+	return nil
+}
+
+// abs returns the absolute form of path, or path itself on error.
+func abs(path string) string {
+	if a, err := filepath.Abs(path); err == nil {
+		return a
+	}
+	return path
+}
+
+// runWxDecrypt implements "isaac wxdecrypt": it decrypts the WeChat media
+// files listed in a manifest with a bounded pool of workers, each owning
+// its own keystream, and reports the status of every file in manifest
+// order.
+func runWxDecrypt(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("wxdecrypt", flag.ContinueOnError)
+	var d wxDecrypter
+	jobs := fs.Int("j", runtime.NumCPU(), "number of files decrypted concurrently")
+	fs.IntVar(&d.prefix, "prefix", isaac.WxPrefixLen, "length of the encrypted prefix in bytes")
+	fs.StringVar(&d.outDir, "out", "", "output directory (default: next to each input)")
+	fs.StringVar(&d.suffix, "suffix", ".dec", "suffix appended to output file names")
+	fs.BoolVar(&d.noVerify, "no-verify", false, "write files whose format is not recognized")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return errors.New("at most one manifest file")
+	}
+	if *jobs < 1 {
+		return fmt.Errorf("invalid -j %d", *jobs)
+	}
+	if d.prefix < 0 {
+		return fmt.Errorf("invalid --prefix %d", d.prefix)
+	}
+
+	manifest := stdin
+	if name := fs.Arg(0); name != "" && name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		manifest = f
+	}
+	list, err := parseManifest(manifest)
+	if err != nil {
+		return err
+	}
+	if err := d.checkOutputs(list); err != nil {
+		return err
+	}
+	if d.outDir != "" {
+		if err := os.MkdirAll(d.outDir, 0o755); err != nil {
+			return err
+		}
+	}
+
+	work := make(chan int)
+	results := make(chan int)
+	done := make([]wxResult, len(list))
+	var wg sync.WaitGroup
+	for range min(*jobs, len(list)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := &wxWorker{d: &d, head: make([]byte, max(d.prefix, headLen))}
+			for i := range work {
+				done[i] = w.decrypt(list[i])
+				results <- i
+			}
+		}()
+	}
+	go func() {
+		for i := range list {
+			work <- i
+		}
+		close(work)
+		wg.Wait()
+		close(results)
+	}()
+
+	// Report in manifest order as soon as every earlier file is finished.
+	finished := make([]bool, len(list))
+	next, failed := 0, 0
+	for i := range results {
+		finished[i] = true
+		for ; next < len(list) && finished[next]; next++ {
+			r := done[next]
+			if r.err != nil {
+				failed++
+				fmt.Fprintf(stdout, "FAIL\t%s\t%v\n", r.job.path, r.err)
+				continue
+			}
+			format := r.format
+			if format == "" {
+				format = "unknown"
+			}
+			fmt.Fprintf(stdout, "ok\t%s\t%s -> %s\n", format, r.job.path, r.out)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d files failed", failed, len(list))
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lbbniu/isaac"
+)
+
+// writeWx writes plain encrypted the way WeChat does to dir/name.
+func writeWx(t *testing.T, dir, name string, key uint64, prefix int, plain []byte) string {
+	t.Helper()
+	var ct bytes.Buffer
+	_, err := isaac.NewWxEncryptWriter(&ct, key, prefix).Write(plain)
+	require.NoError(t, err)
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, ct.Bytes(), 0o600))
+	return path
+}
+
+func TestParseManifest(t *testing.T) {
+	jobs, err := parseManifest(strings.NewReader(`path,key
+# comment
+
+a.mp4,123
+"b, c.jpg",0xff
+{"path": "d.png", "key": 18446744073709551615}
+{"path": "e.gif", "key": "0x10"}
+`))
+	require.NoError(t, err)
+	require.Equal(t, []wxJob{
+		{path: "a.mp4", key: 123},
+		{path: "b, c.jpg", key: 0xff},
+		{path: "d.png", key: 1<<64 - 1},
+		{path: "e.gif", key: 0x10},
+	}, jobs)
+
+	for _, bad := range []string{"a.mp4", "a.mp4,x", "a,1,2", ",1", `{"path": "a"`, `{"path": "a", "key": -1}`} {
+		_, err := parseManifest(strings.NewReader(bad))
+		require.ErrorContains(t, err, "manifest line 1", bad)
+	}
+}
+
+func TestWxDecrypt(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	const prefix = 1000
+	mp4 := append([]byte("\x00\x00\x00\x20ftypisom"), bytes.Repeat([]byte("video "), 500)...)
+	png := []byte("\x89PNG\r\n\x1a\n tiny")
+
+	var manifest strings.Builder
+	want := map[string][]byte{}
+	for i := range 20 {
+		key := uint64(1000 + i)
+		plain := mp4
+		if i%2 == 1 {
+			plain = png
+		}
+		name := fmt.Sprintf("f%02d", i)
+		path := writeWx(t, dir, name, key, prefix, plain)
+		if i == 7 {
+			key++ // wrong key
+		}
+		fmt.Fprintf(&manifest, "%s,%d\n", path, key)
+		want[name] = plain
+	}
+
+	var report bytes.Buffer
+	err := run([]string{"wxdecrypt", "-j", "4", "--prefix", "1000", "--out", out}, strings.NewReader(manifest.String()), &report)
+	require.ErrorContains(t, err, "1 of 20 files failed")
+
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	require.Len(t, lines, 20)
+	for i, line := range lines {
+		name := fmt.Sprintf("f%02d", i)
+		require.Contains(t, line, filepath.Join(dir, name))
+		got, err := os.ReadFile(filepath.Join(out, name+".dec"))
+		if i == 7 {
+			require.True(t, strings.HasPrefix(line, "FAIL\t"), line)
+			require.Contains(t, line, isaac.ErrKeyMismatch.Error())
+			require.ErrorIs(t, err, os.ErrNotExist)
+			continue
+		}
+		format := "mp4"
+		if i%2 == 1 {
+			format = "png"
+		}
+		require.True(t, strings.HasPrefix(line, "ok\t"+format+"\t"), line)
+		require.NoError(t, err)
+		require.Equal(t, want[name], got)
+	}
+}
+
+func TestWxDecryptNoVerify(t *testing.T) {
+	dir := t.TempDir()
+	path := writeWx(t, dir, "doc", 42, isaac.WxPrefixLen, []byte("plain text"))
+	manifest := filepath.Join(dir, "manifest.jsonl")
+	require.NoError(t, os.WriteFile(manifest, []byte(fmt.Sprintf("{\"path\": %q, \"key\": 42}\n", path)), 0o600))
+
+	var report bytes.Buffer
+	require.ErrorContains(t, run([]string{"wxdecrypt", manifest}, nil, &report), "1 of 1")
+	report.Reset()
+	require.NoError(t, run([]string{"wxdecrypt", "--no-verify", "--suffix", ".txt", manifest}, nil, &report))
+	require.Equal(t, fmt.Sprintf("ok\tunknown\t%s -> %s.txt\n", path, path), report.String())
+	got, err := os.ReadFile(path + ".txt")
+	require.NoError(t, err)
+	require.Equal(t, "plain text", string(got))
+
+	report.Reset()
+	err = run([]string{"wxdecrypt", "--no-verify", "--suffix", "", manifest}, nil, &report)
+	require.ErrorContains(t, err, "would overwrite the input")
+	require.Empty(t, report.String())
+}
+
+// TestWxDecryptOutputs checks that entries sharing an output path are
+// rejected before any file is written and that a failed entry leaves an
+// existing output alone.
+func TestWxDecryptOutputs(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	png := []byte("\x89PNG\r\n\x1a\n tiny")
+	for _, sub := range []string{"a", "b"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, sub), 0o755))
+		writeWx(t, filepath.Join(dir, sub), "img", 7, isaac.WxPrefixLen, png)
+	}
+	manifest := fmt.Sprintf("%s,7\n%s,7\n", filepath.Join(dir, "a", "img"), filepath.Join(dir, "b", "img"))
+
+	var report bytes.Buffer
+	err := run([]string{"wxdecrypt", "--out", out}, strings.NewReader(manifest), &report)
+	require.ErrorContains(t, err, "would both be written to")
+	require.NoDirExists(t, out)
+
+	// Without --out the outputs stay apart. Give b a wrong key: its
+	// existing output must survive and no temporary file may be left.
+	existing := filepath.Join(dir, "b", "img.dec")
+	require.NoError(t, os.WriteFile(existing, []byte("keep"), 0o600))
+	manifest = fmt.Sprintf("%s,7\n%s,8\n", filepath.Join(dir, "a", "img"), filepath.Join(dir, "b", "img"))
+	report.Reset()
+	err = run([]string{"wxdecrypt"}, strings.NewReader(manifest), &report)
+	require.ErrorContains(t, err, "1 of 2 files failed")
+
+	got, err := os.ReadFile(filepath.Join(dir, "a", "img.dec"))
+	require.NoError(t, err)
+	require.Equal(t, png, got)
+	got, err = os.ReadFile(existing)
+	require.NoError(t, err)
+	require.Equal(t, "keep", string(got))
+	for _, sub := range []string{"a", "b"} {
+		names, err := filepath.Glob(filepath.Join(dir, sub, ".*"))
+		require.NoError(t, err)
+		require.Empty(t, names)
+	}
+}
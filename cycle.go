@@ -0,0 +1,133 @@
+package isaac
+
+import (
+	"slices"
+	"strings"
+)
+
+// StateClass is a set of flags describing structural weaknesses of a
+// generator state.
+type StateClass uint
+
+const (
+	// ZeroState means the state table and the a, b, c registers are all zero.
+	ZeroState StateClass = 1 << iota
+	// ConstantState means every word of the state table is equal.
+	ConstantState
+	// ConstantOutput means the next result block is one repeated word.
+	ConstantOutput
+	// RepeatedOutput means the next two result blocks are identical.
+	RepeatedOutput
+	// ShortCycle means the state returned to an earlier state within the
+	// search limit given to FindCycle.
+	ShortCycle
+)
+
+var stateClassNames = []string{"zero-state", "constant-state", "constant-output", "repeated-output", "short-cycle"}
+
+// String returns the flag names joined by "|", or "none".
+func (c StateClass) String() string {
+	var names []string
+	for i, name := range stateClassNames {
+		if c&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// CycleReport describes the orbit of a toy state under repeated Refill calls.
+type CycleReport struct {
+	Tail   uint64     // refills before the orbit enters its cycle
+	Period uint64     // cycle length in refills, 0 if no cycle was found
+	Steps  uint64     // refills spent searching
+	Class  StateClass // weaknesses of the starting state
+}
+
+// Classify inspects the current state of s and its next two result blocks
+// without advancing s.
+func Classify[T Word](s *Toy[T]) StateClass {
+	var class StateClass
+	if s.a == 0 && s.b == 0 && s.c == 0 && !slices.ContainsFunc(s.m, func(w T) bool { return w != 0 }) {
+		class |= ZeroState
+	}
+	if !slices.ContainsFunc(s.m, func(w T) bool { return w != s.m[0] }) {
+		class |= ConstantState
+	}
+
+	t := s.Clone()
+	r1 := make([]T, t.Size())
+	r2 := make([]T, t.Size())
+	t.Refill(r1)
+	t.Refill(r2)
+	if !slices.ContainsFunc(r1, func(w T) bool { return w != r1[0] }) {
+		class |= ConstantOutput
+	}
+	if slices.Equal(r1, r2) {
+		class |= RepeatedOutput
+	}
+	return class
+}
+
+// FindCycle walks the orbit of s under Refill with Brent's algorithm, giving
+// up after limit refills, and reports its tail and period together with the
+// class of the starting state. s itself is not advanced.
+//
+// The c register grows by one per refill, so every period is a multiple of
+// 1<<bits; the search is only practical for the 8-bit and 16-bit words.
+func FindCycle[T Word](s *Toy[T], limit uint64) CycleReport {
+	report := CycleReport{Class: Classify(s)}
+	r := make([]T, s.Size())
+	var found bool
+	report.Tail, report.Period, report.Steps, found = brent(s, (*Toy[T]).Clone,
+		func(t *Toy[T]) { t.Refill(r) }, (*Toy[T]).Equal, limit)
+	if found {
+		report.Class |= ShortCycle
+	} else {
+		report.Tail, report.Period = 0, 0
+	}
+	return report
+}
+
+// brent runs Brent's cycle detection on the orbit of start under step, which
+// advances a state in place, giving up after limit steps. It returns the
+// tail and period of the orbit, the steps spent searching for the period
+// and whether a cycle was found. start is not advanced.
+func brent[S any](start S, clone func(S) S, step func(S), equal func(S, S) bool, limit uint64) (tail, period, steps uint64, found bool) {
+	power := uint64(1)
+	period = 1
+	tortoise := clone(start)
+	hare := clone(start)
+	step(hare)
+	steps = 1
+	for !equal(tortoise, hare) {
+		if steps >= limit {
+			return 0, 0, steps, false
+		}
+		if power == period {
+			tortoise = clone(hare)
+			power *= 2
+			period = 0
+		}
+		step(hare)
+		period++
+		steps++
+	}
+
+	// Walk a second pointer period steps ahead, then advance both until
+	// they meet at the start of the cycle.
+	tortoise = clone(start)
+	hare = clone(start)
+	for range period {
+		step(hare)
+	}
+	for !equal(tortoise, hare) {
+		step(tortoise)
+		step(hare)
+		tail++
+	}
+	return tail, period, steps, true
+}
@@ -0,0 +1,98 @@
+package isaac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	s := NewToy[uint8](3)
+	require.Equal(t, StateClass(0), Classify(s))
+
+	clear(s.m)
+	require.Equal(t, ZeroState|ConstantState, Classify(s))
+	require.Equal(t, "zero-state|constant-state", Classify(s).String())
+	require.Equal(t, "none", StateClass(0).String())
+}
+
+func TestFindCycleLimit(t *testing.T) {
+	s := NewToy[uint8](3)
+	before := s.Clone()
+
+	report := FindCycle(s, 1<<12)
+	require.Equal(t, uint64(1<<12), report.Steps)
+	require.Zero(t, report.Period)
+	require.True(t, s.Equal(before))
+}
+
+// TestBrent checks the cycle search behind FindCycle against a brute-force
+// search on small maps x -> x*x+c mod m.
+func TestBrent(t *testing.T) {
+	for _, tc := range []struct{ x, c, m uint64 }{
+		{3, 1, 1000},
+		{2, 7, 9973},
+		{0, 1, 65536},
+		{5, 0, 1},
+	} {
+		f := func(x uint64) uint64 { return (x*x + tc.c) % tc.m }
+
+		seen := make(map[uint64]uint64)
+		var wantTail, wantPeriod uint64
+		for i, x := uint64(0), tc.x%tc.m; ; i, x = i+1, f(x) {
+			if j, ok := seen[x]; ok {
+				wantTail, wantPeriod = j, i-j
+				break
+			}
+			seen[x] = i
+		}
+
+		start := tc.x % tc.m
+		tail, period, _, found := brent(&start,
+			func(p *uint64) *uint64 { v := *p; return &v },
+			func(p *uint64) { *p = f(*p) },
+			func(p, q *uint64) bool { return *p == *q },
+			1<<20)
+		require.True(t, found, tc)
+		require.Equal(t, wantTail, tail, tc)
+		require.Equal(t, wantPeriod, period, tc)
+		require.Equal(t, tc.x%tc.m, start, "start advanced")
+	}
+}
+
+// TestFindCycleToy runs FindCycle on real toy orbits and checks it against
+// a brute-force search that remembers every state. The c register makes
+// every period a multiple of 1<<8 and no toy state is known to lie on a
+// cycle short enough to walk here, so both searches must agree that the
+// first limit states are all distinct.
+func TestFindCycleToy(t *testing.T) {
+	const limit = 1 << 12
+	for _, seed := range [][]uint8{
+		make([]uint8, 8),
+		{1, 2, 3, 4, 5, 6, 7, 8},
+		{0xff, 0, 0xff, 0, 0xff, 0, 0xff, 0},
+	} {
+		s := NewToy[uint8](3)
+		s.Seed(seed)
+
+		type state struct {
+			m       [8]uint8
+			a, b, c uint8
+		}
+		seen := make(map[state]bool)
+		w := s.Clone()
+		r := make([]uint8, w.Size())
+		for range limit {
+			k := state{m: [8]uint8(w.m), a: w.a, b: w.b, c: w.c}
+			require.False(t, seen[k], "brute force found a repeat")
+			seen[k] = true
+			w.Refill(r)
+		}
+
+		report := FindCycle(s, limit)
+		require.Equal(t, uint64(limit), report.Steps)
+		require.Zero(t, report.Tail)
+		require.Zero(t, report.Period)
+		require.Zero(t, report.Class&ShortCycle)
+	}
+}
@@ -0,0 +1,200 @@
+package isaac
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Limits of DRBG, in the roles SP 800-90A gives them. The security strength
+// is 256 bits.
+const (
+	DRBGMinEntropy     = 32      // minimum entropy input length in bytes
+	DRBGMinNonce       = 16      // minimum nonce length in bytes
+	DRBGMaxInput       = 1 << 16 // maximum length of any input in bytes
+	DRBGMaxRequest     = 1 << 16 // maximum bytes per Generate call
+	DRBGReseedInterval = 1 << 32 // Generate calls allowed between reseeds
+)
+
+// Errors returned by DRBG.
+var (
+	ErrNotInstantiated = errors.New("isaac: DRBG not instantiated")
+	ErrReseedRequired  = errors.New("isaac: DRBG reseed required")
+	ErrRequestTooLarge = errors.New("isaac: DRBG request too large")
+	ErrEntropyInput    = errors.New("isaac: DRBG entropy input too short")
+	ErrInputTooLong    = errors.New("isaac: DRBG input too long")
+)
+
+// DRBG is an ISAAC-64 based deterministic random bit generator with the call
+// shapes of NIST SP 800-90A: Instantiate, Generate, Reseed and
+// Uninstantiate, a reseed counter and request size limits. It is not an
+// approved SP 800-90A mechanism; it gives RNGs in one code base a uniform
+// interface.
+//
+// Inputs are folded into the state with AddEntropy, each followed by its
+// length so inputs cannot run into each other. The state uses fast key
+// erasure (see ISAAC.SetKeyErasure), so output of earlier Generate calls
+// cannot be recomputed from a later state, and the unused rest of the last
+// block of a request is discarded. The zero value is uninstantiated; a
+// DRBG is safe for concurrent use.
+type DRBG struct {
+	mu      sync.Mutex
+	s       *ISAAC[uint64] // nil when uninstantiated
+	counter uint64         // reseed counter: Generate calls since (re)seeding, plus one
+	limit   uint64         // reseed interval, DRBGReseedInterval if zero
+	block   [Words]uint64
+}
+
+// checkInput validates the length of one input.
+func checkInput(name string, p []byte) error {
+	if len(p) > DRBGMaxInput {
+		return fmt.Errorf("%w: %s of %d bytes", ErrInputTooLong, name, len(p))
+	}
+	return nil
+}
+
+// absorb folds the inputs into the state, each followed by its length as a
+// little-endian 64-bit value.
+func (d *DRBG) absorb(inputs ...[]byte) {
+	var n int
+	for _, p := range inputs {
+		n += len(p) + 8
+	}
+	buf := make([]byte, 0, n)
+	for _, p := range inputs {
+		buf = append(buf, p...)
+		buf = binary.LittleEndian.AppendUint64(buf, uint64(len(p)))
+	}
+	d.s.AddEntropy(buf)
+	wipe(buf)
+}
+
+// Instantiate seeds the DRBG from entropy input of at least DRBGMinEntropy
+// bytes, a nonce of at least DRBGMinNonce bytes and an optional
+// personalization string, replacing any earlier instantiation.
+func (d *DRBG) Instantiate(entropy, nonce, personalization []byte) error {
+	if len(entropy) < DRBGMinEntropy {
+		return fmt.Errorf("%w: %d bytes, need %d", ErrEntropyInput, len(entropy), DRBGMinEntropy)
+	}
+	if len(nonce) < DRBGMinNonce {
+		return fmt.Errorf("isaac: DRBG nonce of %d bytes, need %d", len(nonce), DRBGMinNonce)
+	}
+	for _, in := range []struct {
+		name string
+		p    []byte
+	}{{"entropy input", entropy}, {"nonce", nonce}, {"personalization string", personalization}} {
+		if err := checkInput(in.name, in.p); err != nil {
+			return err
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.uninstantiate()
+	d.s = New[uint64]()
+	d.s.SetKeyErasure(true)
+	d.absorb(entropy, nonce, personalization)
+	d.counter = 1
+	return nil
+}
+
+// Reseed folds fresh entropy input of at least DRBGMinEntropy bytes and
+// optional additional input into the state and resets the reseed counter.
+func (d *DRBG) Reseed(entropy, additionalInput []byte) error {
+	if len(entropy) < DRBGMinEntropy {
+		return fmt.Errorf("%w: %d bytes, need %d", ErrEntropyInput, len(entropy), DRBGMinEntropy)
+	}
+	if err := checkInput("entropy input", entropy); err != nil {
+		return err
+	}
+	if err := checkInput("additional input", additionalInput); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.s == nil {
+		return ErrNotInstantiated
+	}
+	d.absorb(entropy, additionalInput)
+	d.counter = 1
+	return nil
+}
+
+// Generate fills out with random bytes, first folding in additionalInput if
+// it is not empty. out may be at most DRBGMaxRequest bytes. Once the reseed
+// counter passes the reseed interval Generate returns ErrReseedRequired
+// until Reseed is called.
+func (d *DRBG) Generate(out, additionalInput []byte) error {
+	if len(out) > DRBGMaxRequest {
+		return fmt.Errorf("%w: %d bytes, at most %d", ErrRequestTooLarge, len(out), DRBGMaxRequest)
+	}
+	if err := checkInput("additional input", additionalInput); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.s == nil {
+		return ErrNotInstantiated
+	}
+	limit := d.limit
+	if limit == 0 {
+		limit = DRBGReseedInterval
+	}
+	if d.counter > limit {
+		return ErrReseedRequired
+	}
+	if len(additionalInput) > 0 {
+		d.absorb(additionalInput)
+	}
+
+	var word [8]byte
+	for len(out) > 0 {
+		d.s.Refill(&d.block)
+		for _, w := range d.block {
+			binary.LittleEndian.PutUint64(word[:], w)
+			n := copy(out, word[:])
+			out = out[n:]
+			if len(out) == 0 {
+				break
+			}
+		}
+	}
+	wipe(d.block[:])
+	wipe(word[:])
+	d.counter++
+	return nil
+}
+
+// ReseedCounter returns the number of Generate calls since the last
+// Instantiate or Reseed plus one, as SP 800-90A counts it, or 0 when
+// uninstantiated.
+func (d *DRBG) ReseedCounter() uint64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.counter
+}
+
+// Uninstantiate zeroes the state. Generate and Reseed return
+// ErrNotInstantiated until the next Instantiate.
+func (d *DRBG) Uninstantiate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.uninstantiate()
+}
+
+func (d *DRBG) uninstantiate() {
+	if d.s != nil {
+		d.s.Destroy()
+		d.s = nil
+	}
+	wipe(d.block[:])
+	d.counter = 0
+}
@@ -0,0 +1,92 @@
+package isaac
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testEntropy = bytes.Repeat([]byte{0xe5}, DRBGMinEntropy)
+	testNonce   = bytes.Repeat([]byte{0x17}, DRBGMinNonce)
+)
+
+func newTestDRBG(t *testing.T, personalization string) *DRBG {
+	t.Helper()
+	var d DRBG
+	require.NoError(t, d.Instantiate(testEntropy, testNonce, []byte(personalization)))
+	return &d
+}
+
+func generate(t *testing.T, d *DRBG, n int, additionalInput string) []byte {
+	t.Helper()
+	out := make([]byte, n)
+	require.NoError(t, d.Generate(out, []byte(additionalInput)))
+	return out
+}
+
+func TestDRBGDeterministic(t *testing.T) {
+	d1, d2 := newTestDRBG(t, "app"), newTestDRBG(t, "app")
+	for _, n := range []int{1, 100, 3000, DRBGMaxRequest} {
+		require.Equal(t, generate(t, d1, n, ""), generate(t, d2, n, ""))
+	}
+	require.Equal(t, generate(t, d1, 64, "x"), generate(t, d2, 64, "x"))
+
+	// Every input changes the output.
+	ref := generate(t, newTestDRBG(t, "app"), 64, "")
+	require.NotEqual(t, ref, generate(t, newTestDRBG(t, "other"), 64, ""))
+	require.NotEqual(t, ref, generate(t, newTestDRBG(t, "app"), 64, "additional"))
+
+	d := newTestDRBG(t, "app")
+	require.NoError(t, d.Reseed(testEntropy, nil))
+	require.NotEqual(t, ref, generate(t, d, 64, ""))
+
+	// Inputs are length-delimited.
+	var a, b DRBG
+	require.NoError(t, a.Instantiate(testEntropy, append(testNonce, 'p'), nil))
+	require.NoError(t, b.Instantiate(testEntropy, testNonce, []byte{'p'}))
+	require.NotEqual(t, generate(t, &a, 64, ""), generate(t, &b, 64, ""))
+}
+
+func TestDRBGRequestsDoNotOverlap(t *testing.T) {
+	d := newTestDRBG(t, "")
+	first := generate(t, d, 16, "")
+	second := generate(t, d, 16, "")
+	require.NotEqual(t, first, second)
+}
+
+func TestDRBGReseedCounter(t *testing.T) {
+	d := newTestDRBG(t, "")
+	d.limit = 2
+	require.Equal(t, uint64(1), d.ReseedCounter())
+	generate(t, d, 8, "")
+	generate(t, d, 8, "")
+	require.Equal(t, uint64(3), d.ReseedCounter())
+	require.ErrorIs(t, d.Generate(make([]byte, 8), nil), ErrReseedRequired)
+
+	require.NoError(t, d.Reseed(testEntropy, []byte("more")))
+	require.Equal(t, uint64(1), d.ReseedCounter())
+	generate(t, d, 8, "")
+}
+
+func TestDRBGErrors(t *testing.T) {
+	var d DRBG
+	require.ErrorIs(t, d.Generate(make([]byte, 8), nil), ErrNotInstantiated)
+	require.ErrorIs(t, d.Reseed(testEntropy, nil), ErrNotInstantiated)
+	require.Zero(t, d.ReseedCounter())
+
+	require.ErrorIs(t, d.Instantiate(testEntropy[1:], testNonce, nil), ErrEntropyInput)
+	require.ErrorContains(t, d.Instantiate(testEntropy, testNonce[1:], nil), "nonce")
+	require.ErrorIs(t, d.Instantiate(testEntropy, testNonce, make([]byte, DRBGMaxInput+1)), ErrInputTooLong)
+
+	require.NoError(t, d.Instantiate(testEntropy, testNonce, nil))
+	require.ErrorIs(t, d.Generate(make([]byte, DRBGMaxRequest+1), nil), ErrRequestTooLarge)
+	require.ErrorIs(t, d.Generate(make([]byte, 8), make([]byte, DRBGMaxInput+1)), ErrInputTooLong)
+	require.ErrorIs(t, d.Reseed(testEntropy[1:], nil), ErrEntropyInput)
+
+	d.Uninstantiate()
+	require.ErrorIs(t, d.Generate(make([]byte, 8), nil), ErrNotInstantiated)
+	require.NoError(t, d.Instantiate(testEntropy, testNonce, nil))
+	generate(t, &d, 8, "")
+}
@@ -0,0 +1,103 @@
+package isaac
+
+// foldWords XORs words into the state table m, one table-sized chunk at a
+// time, and mixes m after each chunk with the two passes Seed uses, so
+// every word affects the whole table and the existing state is kept.
+func foldWords[T Word](m *[Words]T, words []T, v [8]T) {
+	for len(words) > 0 {
+		n := min(len(words), Words)
+		for i, w := range words[:n] {
+			m[i] ^= w
+		}
+		seedState(m[:], v)
+		words = words[n:]
+	}
+}
+
+// packWords packs p into words little-endian, zero padding the last word.
+func packWords[T Word](p []byte) []T {
+	size := int(paramsOf[T]().bits / 8)
+	words := make([]T, (len(p)+size-1)/size)
+	for i, b := range p {
+		words[i/size] |= T(b) << (8 * (i % size))
+	}
+	return words
+}
+
+// Mix folds words into the state table: each table-sized chunk is XORed in
+// and mixed with the two passes of Seed, starting from the initial values
+// given to the last Seed, or the default ones. The a, b and c registers are kept and results buffered for Rand
+// are dropped, so all later output depends on the new material. Mixing
+// into a live generator only adds entropy; it never discards what the
+// state already holds.
+func (s *ISAAC[T]) Mix(words []T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
+	v := InitValues[T]()
+	if s.custom {
+		v = s.init
+	}
+	foldWords(&s.m, words, v)
+	wipe(s.r[:])
+	s.n = 0
+}
+
+// AddEntropy mixes p into the state as Mix does, packing the bytes into
+// words little-endian.
+func (s *ISAAC[T]) AddEntropy(p []byte) {
+	words := packWords[T](p)
+	s.Mix(words)
+	wipe(words)
+}
+
+// Mix folds words into the state table, see ISAAC.Mix.
+func (s *ISAAC32) Mix(words []uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
+	v := defaultInit32
+	if s.custom {
+		v = s.init
+	}
+	foldWords(&s.m, words, v)
+	wipe(s.r[:])
+	s.n = 0
+}
+
+// AddEntropy mixes p into the state, see ISAAC.AddEntropy.
+func (s *ISAAC32) AddEntropy(p []byte) {
+	words := packWords[uint32](p)
+	s.Mix(words)
+	wipe(words)
+}
+
+// Mix folds words into the state table, see ISAAC.Mix.
+func (s *ISAAC64) Mix(words []uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
+	v := defaultInit64
+	if s.custom {
+		v = s.init
+	}
+	foldWords(&s.m, words, v)
+	wipe(s.r[:])
+	s.n = 0
+}
+
+// AddEntropy mixes p into the state, see ISAAC.AddEntropy.
+func (s *ISAAC64) AddEntropy(p []byte) {
+	words := packWords[uint64](p)
+	s.Mix(words)
+	wipe(words)
+}
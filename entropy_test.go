@@ -0,0 +1,135 @@
+package isaac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMix(t *testing.T) {
+	s := New[uint64]()
+	var r [Words]uint64
+	s.Refill(&r)
+	m, a, b, c := s.m, s.a, s.b, s.c
+
+	words := []uint64{1, 2, 3}
+	s.Mix(words)
+	for i, w := range words {
+		m[i] ^= w
+	}
+	seedState(m[:], InitValues[uint64]())
+	require.Equal(t, m, s.m)
+	require.Equal(t, []uint64{a, b, c}, []uint64{s.a, s.b, s.c})
+}
+
+func TestMixInitValues(t *testing.T) {
+	var v [8]uint64
+	for i := range v {
+		v[i] = uint64(i+1) * 0x0123456789abcdef
+	}
+	var seed [Words]uint64
+	seed[0] = 7
+	words := []uint64{1, 2, 3}
+
+	// Mix starts from the initial values the state was seeded with.
+	s := New[uint64]()
+	s.Seed(seed, v[:]...)
+	m := s.m
+	s.Mix(words)
+	for i, w := range words {
+		m[i] ^= w
+	}
+	seedState(m[:], v)
+	require.Equal(t, m, s.m)
+
+	s64 := New64()
+	s64.Seed(seed, v[:]...)
+	s64.Mix(words)
+	require.Equal(t, s.m, s64.m)
+
+	s32, g32 := New32(), New[uint32]()
+	var v32 [8]uint32
+	for i := range v32 {
+		v32[i] = uint32(v[i])
+	}
+	s32.Seed([Words]uint32{7}, v32[:]...)
+	g32.Seed([Words]uint32{7}, v32[:]...)
+	s32.Mix([]uint32{1, 2, 3})
+	g32.Mix([]uint32{1, 2, 3})
+	require.Equal(t, g32.m, s32.m)
+
+	// Generators built with WithInitValues keep them too.
+	g, err := NewGenerator(WithSeed(seed[:1]), WithInitValues(v[:]...))
+	require.NoError(t, err)
+	g.AddEntropy([]byte{1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 3})
+	ref := New[uint64]()
+	ref.Seed(seed, v[:]...)
+	ref.Mix(words)
+	var want [Words]uint64
+	ref.Refill(&want)
+	require.Equal(t, want[0], g.Uint64())
+}
+
+func TestMixChunks(t *testing.T) {
+	words := make([]uint32, 2*Words+10)
+	for i := range words {
+		words[i] = uint32(i * 7919)
+	}
+	s1, s2 := New[uint32](), New[uint32]()
+	s1.Mix(words)
+	s2.Mix(words[:Words])
+	s2.Mix(words[Words : 2*Words])
+	s2.Mix(words[2*Words:])
+	require.Equal(t, s1.m, s2.m)
+}
+
+func TestMixDropsBuffered(t *testing.T) {
+	s := New[uint64]()
+	s.Rand()
+	s.Mix([]uint64{42})
+
+	ref := New[uint64]()
+	var r [Words]uint64
+	ref.Refill(&r)
+	ref.Mix([]uint64{42})
+	ref.Refill(&r)
+	require.Equal(t, r[0], s.Rand())
+}
+
+func TestAddEntropy(t *testing.T) {
+	s1, s2 := New[uint32](), New[uint32]()
+	s1.AddEntropy([]byte{1, 2, 3, 4, 5})
+	s2.Mix([]uint32{0x04030201, 5})
+	require.Equal(t, s1.m, s2.m)
+
+	// The concrete types match the generic one.
+	g, c32 := New[uint32](), New32()
+	g.AddEntropy([]byte("more entropy"))
+	c32.AddEntropy([]byte("more entropy"))
+	for range Words + 1 {
+		require.Equal(t, g.Rand(), c32.Rand())
+	}
+	g64, c64 := New[uint64](), New64()
+	g64.AddEntropy([]byte("more entropy"))
+	c64.AddEntropy([]byte("more entropy"))
+	for range Words + 1 {
+		require.Equal(t, g64.Rand(), c64.Rand())
+	}
+}
+
+func TestGeneratorAddEntropy(t *testing.T) {
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	g.Uint64()
+	g.AddEntropy([]byte("more entropy"))
+
+	s := New[uint64]()
+	s.Rand()
+	s.AddEntropy([]byte("more entropy"))
+	for range Words {
+		require.Equal(t, s.Rand(), g.Uint64())
+	}
+
+	g.Destroy()
+	require.PanicsWithValue(t, ErrDestroyed, func() { g.AddEntropy([]byte{1}) })
+}
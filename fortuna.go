@@ -0,0 +1,225 @@
+package isaac
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+	"time"
+)
+
+// Parameters of the Fortuna accumulator, from Ferguson, Schneier and
+// Kohno, Cryptography Engineering, chapter 9.
+const (
+	FortunaPools          = 32                     // number of entropy pools
+	FortunaMinPoolSize    = 64                     // bytes in pool 0 before a reseed
+	FortunaReseedInterval = 100 * time.Millisecond // minimum time between reseeds
+	FortunaMaxEventSize   = 32                     // maximum bytes of one event
+)
+
+// Sources of events gathered by Fortuna itself. Applications pass their own
+// source numbers, from SourceApp up, to AddEvent.
+const (
+	SourceCryptoRand byte = iota
+	SourceJitter
+	SourceApp
+)
+
+// ErrNotSeeded is returned by Fortuna.Read before the first reseed.
+var ErrNotSeeded = errors.New("isaac: Fortuna generator not seeded yet")
+
+// Fortuna is a Fortuna-style entropy accumulator feeding an ISAAC-64
+// generator. Events from several sources are spread round-robin over 32
+// SHA-256 pools; once pool 0 holds enough data and the reseed interval has
+// passed, the next Read reseeds the generator with the double SHA-256 of
+// pool i for every i where 2^i divides the reseed count, so higher pools
+// are used ever more rarely and eventually outlast an attacker who can
+// predict some sources. Seeds are folded in with AddEntropy, keeping the
+// existing state, and the generator runs with fast key erasure (see
+// ISAAC.SetKeyErasure). The generator is ISAAC[uint64] rather than ISAAC64
+// because only the generic kernel offers key erasure; with erasure off the
+// two give the same output word for word. It is safe for concurrent use.
+type Fortuna struct {
+	mu        sync.Mutex
+	pools     [FortunaPools]hash.Hash
+	pool0     int           // bytes added to pool 0 since the last reseed
+	next      [256]int      // next pool of each source
+	reseeds   uint64        // reseed count
+	last      time.Time     // time of the last reseed
+	s         ISAAC[uint64] // the generator
+	block     [Words]uint64
+	entropy   io.Reader // source of GatherCryptoRand
+	now       func() time.Time
+	jitterAt  time.Time // time of the previous GatherJitter call
+	destroyed bool
+}
+
+// NewFortuna creates an accumulator with empty pools. Reads fail with
+// ErrNotSeeded until events have been added and a reseed happened; call
+// Run, or the Gather methods and AddEvent, to feed it.
+func NewFortuna() *Fortuna {
+	f := &Fortuna{entropy: rand.Reader, now: time.Now}
+	for i := range f.pools {
+		f.pools[i] = sha256.New()
+	}
+	f.s.SetKeyErasure(true)
+	return f
+}
+
+// AddEvent adds an event of 1 to FortunaMaxEventSize bytes from source to
+// the source's next pool. It returns ErrDestroyed after Destroy.
+func (f *Fortuna) AddEvent(source byte, data []byte) error {
+	if len(data) == 0 || len(data) > FortunaMaxEventSize {
+		return fmt.Errorf("isaac: Fortuna event of %d bytes, want 1 to %d", len(data), FortunaMaxEventSize)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.destroyed {
+		return ErrDestroyed
+	}
+	i := f.next[source]
+	f.next[source] = (i + 1) % FortunaPools
+	f.pools[i].Write([]byte{source, byte(len(data))})
+	f.pools[i].Write(data)
+	if i == 0 {
+		f.pool0 += 2 + len(data)
+	}
+	return nil
+}
+
+// GatherCryptoRand adds an event of FortunaMaxEventSize bytes from
+// crypto/rand.
+func (f *Fortuna) GatherCryptoRand() error {
+	var b [FortunaMaxEventSize]byte
+	defer wipe(b[:])
+	if _, err := io.ReadFull(f.entropy, b[:]); err != nil {
+		return fmt.Errorf("isaac: gathering entropy: %w", err)
+	}
+	return f.AddEvent(SourceCryptoRand, b[:])
+}
+
+// GatherJitter adds an event from timing jitter: the time since the
+// previous call and the time taken by a short busy loop, both in
+// nanoseconds. Each event carries little entropy; it is one source among
+// several.
+func (f *Fortuna) GatherJitter() error {
+	start := time.Now()
+	x := uint64(start.UnixNano())
+	for i := range 1 << 10 {
+		x = x*6364136223846793005 + uint64(i)
+	}
+	end := time.Now()
+
+	f.mu.Lock()
+	since := end.Sub(f.jitterAt)
+	f.jitterAt = end
+	f.mu.Unlock()
+
+	var b [24]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(since))
+	binary.LittleEndian.PutUint64(b[8:], uint64(end.Sub(start)))
+	binary.LittleEndian.PutUint64(b[16:], x)
+	return f.AddEvent(SourceJitter, b[:])
+}
+
+// Run gathers a crypto/rand and a jitter event every interval until ctx is
+// done, and returns the first error of GatherCryptoRand or ctx.Err().
+func (f *Fortuna) Run(ctx context.Context, interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		if err := f.GatherCryptoRand(); err != nil {
+			return err
+		}
+		_ = f.GatherJitter()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// reseed reseeds the generator if pool 0 is full enough and the interval
+// has passed. The caller holds mu.
+func (f *Fortuna) reseed() {
+	now := f.now()
+	if f.pool0 < FortunaMinPoolSize || (f.reseeds > 0 && now.Sub(f.last) < FortunaReseedInterval) {
+		return
+	}
+	f.reseeds++
+	seed := make([]byte, 0, FortunaPools*sha256.Size)
+	for i, p := range f.pools {
+		if f.reseeds%(1<<i) != 0 {
+			break
+		}
+		d := sha256.Sum256(p.Sum(nil))
+		seed = append(seed, d[:]...)
+		p.Reset()
+	}
+	f.s.AddEntropy(seed)
+	wipe(seed)
+	f.pool0 = 0
+	f.last = now
+}
+
+// Read fills p with generator output, reseeding first when due. It returns
+// ErrNotSeeded until the first reseed and ErrDestroyed after Destroy. The
+// rest of the last result block is discarded, so no output is kept for
+// later calls.
+func (f *Fortuna) Read(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.destroyed {
+		return 0, ErrDestroyed
+	}
+	f.reseed()
+	if f.reseeds == 0 {
+		return 0, ErrNotSeeded
+	}
+	n := 0
+	for n < len(p) {
+		f.s.Refill(&f.block)
+		for _, w := range f.block {
+			var b [8]byte
+			binary.LittleEndian.PutUint64(b[:], w)
+			n += copy(p[n:], b[:])
+			if n == len(p) {
+				break
+			}
+		}
+	}
+	wipe(f.block[:])
+	return n, nil
+}
+
+// Reseeds returns the number of reseeds so far.
+func (f *Fortuna) Reseeds() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.reseeds
+}
+
+// Destroy resets the pools and zeroes the generator state. Later calls to
+// Read and AddEvent return ErrDestroyed, whether or not the generator was
+// seeded.
+func (f *Fortuna) Destroy() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, p := range f.pools {
+		p.Reset()
+	}
+	f.pool0 = 0
+	f.s.Destroy()
+	f.destroyed = true
+}
@@ -0,0 +1,146 @@
+package isaac
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFortuna returns an accumulator with a controllable clock.
+func newTestFortuna() (*Fortuna, *time.Time) {
+	f := NewFortuna()
+	now := time.Unix(1000, 0)
+	f.now = func() time.Time { return now }
+	return f, &now
+}
+
+// fill adds events of 32 bytes from source until pool 0 is full enough.
+func fill(t *testing.T, f *Fortuna, source byte) {
+	t.Helper()
+	for range FortunaPools * 3 {
+		require.NoError(t, f.AddEvent(source, bytes.Repeat([]byte{source}, FortunaMaxEventSize)))
+	}
+}
+
+func TestFortunaSeeding(t *testing.T) {
+	f, now := newTestFortuna()
+	_, err := f.Read(make([]byte, 8))
+	require.ErrorIs(t, err, ErrNotSeeded)
+
+	// One event per pool is not enough for pool 0.
+	for range FortunaPools {
+		require.NoError(t, f.AddEvent(SourceApp, []byte{1}))
+	}
+	_, err = f.Read(make([]byte, 8))
+	require.ErrorIs(t, err, ErrNotSeeded)
+
+	fill(t, f, SourceApp)
+	out := make([]byte, 3000)
+	n, err := f.Read(out)
+	require.NoError(t, err)
+	require.Equal(t, len(out), n)
+	require.Equal(t, uint64(1), f.Reseeds())
+
+	// The next reseed waits for both new data and the interval.
+	fill(t, f, SourceApp)
+	_, _ = f.Read(out)
+	require.Equal(t, uint64(1), f.Reseeds())
+	*now = now.Add(FortunaReseedInterval)
+	_, _ = f.Read(out)
+	require.Equal(t, uint64(2), f.Reseeds())
+	*now = now.Add(FortunaReseedInterval)
+	_, _ = f.Read(out)
+	require.Equal(t, uint64(2), f.Reseeds())
+}
+
+func TestFortunaDeterministic(t *testing.T) {
+	f1, _ := newTestFortuna()
+	f2, _ := newTestFortuna()
+	f3, _ := newTestFortuna()
+	fill(t, f1, SourceApp)
+	fill(t, f2, SourceApp)
+	fill(t, f3, SourceApp+1)
+
+	b1, b2, b3 := make([]byte, 64), make([]byte, 64), make([]byte, 64)
+	_, _ = f1.Read(b1)
+	_, _ = f2.Read(b2)
+	_, _ = f3.Read(b3)
+	require.Equal(t, b1, b2)
+	require.NotEqual(t, b1, b3)
+
+	// Reads never repeat output.
+	_, _ = f2.Read(b2)
+	require.NotEqual(t, b1, b2)
+}
+
+func TestFortunaPoolSchedule(t *testing.T) {
+	f, now := newTestFortuna()
+	for r := uint64(1); r <= 8; r++ {
+		fill(t, f, SourceApp)
+		*now = now.Add(FortunaReseedInterval)
+		_, err := f.Read(make([]byte, 1))
+		require.NoError(t, err)
+		require.Equal(t, r, f.Reseeds())
+
+		// Pools 0 to k were emptied, where 2^k is the largest power of
+		// two dividing r.
+		empty := sha256.New().Sum(nil)
+		for i := range 4 {
+			used := r%(1<<i) == 0
+			require.Equal(t, used, bytes.Equal(f.pools[i].Sum(nil), empty), "reseed %d pool %d", r, i)
+		}
+	}
+}
+
+func TestFortunaEvents(t *testing.T) {
+	f := NewFortuna()
+	require.Error(t, f.AddEvent(SourceApp, nil))
+	require.Error(t, f.AddEvent(SourceApp, make([]byte, FortunaMaxEventSize+1)))
+	require.NoError(t, f.AddEvent(SourceApp, []byte{1}))
+	require.NoError(t, f.AddEvent(SourceApp, []byte{1}))
+	require.Equal(t, 2, f.next[SourceApp])
+	require.Equal(t, 0, f.next[SourceApp+1])
+	require.Equal(t, 3, f.pool0)
+
+	require.NoError(t, f.GatherJitter())
+	require.NoError(t, f.GatherCryptoRand())
+	require.Equal(t, 1, f.next[SourceJitter])
+	require.Equal(t, 1, f.next[SourceCryptoRand])
+}
+
+func TestFortunaRun(t *testing.T) {
+	// With ctx already done, Run gathers exactly one round and returns.
+	f, _ := newTestFortuna()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, f.Run(ctx, time.Hour), context.Canceled)
+	require.Equal(t, 1, f.next[SourceCryptoRand])
+	require.Equal(t, 1, f.next[SourceJitter])
+
+	f.entropy = iotest.ErrReader(errors.New("no entropy"))
+	require.ErrorContains(t, f.Run(context.Background(), time.Hour), "no entropy")
+}
+
+func TestFortunaDestroy(t *testing.T) {
+	// Unseeded and seeded generators fail the same way after Destroy.
+	f, _ := newTestFortuna()
+	f.Destroy()
+	_, err := f.Read(make([]byte, 1))
+	require.ErrorIs(t, err, ErrDestroyed)
+
+	f, _ = newTestFortuna()
+	fill(t, f, SourceApp)
+	_, err = f.Read(make([]byte, 16))
+	require.NoError(t, err)
+	f.Destroy()
+	_, err = f.Read(make([]byte, 1))
+	require.ErrorIs(t, err, ErrDestroyed)
+	require.ErrorIs(t, f.AddEvent(SourceApp, []byte{1}), ErrDestroyed)
+	require.ErrorIs(t, f.GatherCryptoRand(), ErrDestroyed)
+}
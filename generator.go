@@ -0,0 +1,432 @@
+package isaac
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"sync"
+)
+
+// Generator is an ISAAC output stream configured by NewGenerator. It is safe
+// for concurrent use.
+type Generator interface {
+	// Read fills p with output bytes laid out by the profile and byte
+	// order. It returns len(p), nil, or 0, ErrDestroyed after Destroy.
+	io.Reader
+	// Uint32 returns the next word truncated to 32 bits.
+	Uint32() uint32
+	// Uint64 returns the next word, or the next two words for the 32-bit
+	// kernel, combined as the profile specifies.
+	Uint64() uint64
+	// Width returns the word size of the kernel in bits, 32 or 64.
+	Width() int
+	// AddEntropy mixes p into the live state, packed into words with the
+	// configured byte order, and drops buffered output. See ISAAC.Mix.
+	AddEntropy(p []byte)
+	// Destroy zeroes the generator state and buffered output. Later calls
+	// to Read return ErrDestroyed; Uint32 and Uint64 panic with it.
+	Destroy()
+}
+
+// NewGenerator creates a Generator from the given options. Without options
+// it returns a 64-bit Coreutils generator seeded with zeros, the same
+// stream as New[uint64].
+//
+// Mixing Read with the word methods discards the bytes left over from a
+// partially read word.
+func NewGenerator(opts ...Option) (Generator, error) {
+	g, _, err := build(opts)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// build applies opts and creates the generator they describe.
+func build(opts []Option) (keystream, *config, error) {
+	var c config
+	for _, opt := range opts {
+		if err := opt(&c); err != nil {
+			return nil, nil, err
+		}
+	}
+	if c.selfTest {
+		if err := selfTestOnce(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	switch {
+	case c.width == 0 && c.wordWidth == 0:
+		c.width = 64
+	case c.width == 0:
+		c.width = c.wordWidth
+	case c.wordWidth != 0 && c.wordWidth != c.width:
+		return nil, nil, fmt.Errorf("%w: %d-bit words given to a %d-bit generator", ErrWidth, c.wordWidth, c.width)
+	}
+	if c.entropy != nil && (c.seed != nil || c.seedWords != nil) {
+		return nil, nil, ErrSeedSources
+	}
+	if c.nonce != nil && c.seed == nil {
+		return nil, nil, ErrNonce
+	}
+	if c.health != nil && c.entropy != nil {
+		c.entropy = NewHealthReader(c.entropy, c.health)
+	}
+
+	l, err := c.profile.layout()
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.order != nil {
+		l.order = c.order
+	}
+
+	var g keystream
+	if c.width == 32 {
+		g, err = newGenerator[uint32](&c, l)
+	} else {
+		g, err = newGenerator[uint64](&c, l)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return g, &c, nil
+}
+
+// generator implements Generator over the ISAAC[T] kernel.
+type generator[T uint32 | uint64] struct {
+	mu sync.Mutex
+	s  ISAAC[T]
+	layout
+	block     [Words]T
+	n         int    // unread words left in block
+	pending   []byte // unread bytes of a partially read word
+	tmp       [8]byte
+	destroyed bool
+	health    *HealthTest
+	digest    hash.Hash         // SHA-256 for the stuck-output check
+	prev      [sha256.Size]byte // digest of the previous block
+	err       error             // sticky health failure
+}
+
+func newGenerator[T uint32 | uint64](c *config, l layout) (*generator[T], error) {
+	seed, err := seedTable[T](c, l.order)
+	if err != nil {
+		return nil, err
+	}
+	if c.strict {
+		if err := strictSeed(c, &seed); err != nil {
+			wipe(seed[:])
+			return nil, err
+		}
+	}
+	var init []T
+	for _, v := range c.initValues {
+		init = append(init, T(v))
+	}
+
+	g := &generator[T]{layout: l}
+	g.s.Seed(seed, init...)
+	g.s.SetConstantTime(c.ct)
+	g.s.SetKeyErasure(c.erase)
+	if c.health != nil {
+		g.health, g.digest = c.health, sha256.New()
+	}
+	return g, nil
+}
+
+// seedTable builds the seed array from the configured seed or entropy
+// source, packing bytes into words with order.
+func seedTable[T uint32 | uint64](c *config, order binary.ByteOrder) ([Words]T, error) {
+	var seed [Words]T
+	size := wordSize[T]()
+
+	b := c.seed
+	if c.entropy != nil {
+		b = make([]byte, Words*size)
+		if _, err := io.ReadFull(c.entropy, b); err != nil {
+			return seed, fmt.Errorf("isaac: reading entropy: %w", err)
+		}
+	}
+
+	switch {
+	case c.seedWords != nil:
+		if len(c.seedWords) > Words {
+			return seed, fmt.Errorf("%w: %d words", ErrSeedLength, len(c.seedWords))
+		}
+		for i, w := range c.seedWords {
+			seed[i] = T(w)
+		}
+	case c.nonce != nil:
+		return keyedSeed[T](b, c.nonce, order)
+	case b != nil:
+		if len(b) > Words*size {
+			return seed, fmt.Errorf("%w: %d bytes", ErrSeedLength, len(b))
+		}
+		buf := make([]byte, Words*size)
+		copy(buf, b)
+		for i := range seed {
+			seed[i] = getWord[T](order, buf[i*size:])
+		}
+	}
+	return seed, nil
+}
+
+// strictSeed runs the checks of CheckSeed for WithStrictSeed on the seed
+// material the caller supplied, before padding: the seed words or bytes, or
+// the key of WithNonce. A table read with WithEntropy, or the zero table
+// used without a seed, is checked whole.
+func strictSeed[T uint32 | uint64](c *config, seed *[Words]T) error {
+	if err := checkPublished(seed); err != nil {
+		return err
+	}
+	switch {
+	case c.seedWords != nil:
+		words := make([]T, len(c.seedWords))
+		for i, w := range c.seedWords {
+			words[i] = T(w)
+		}
+		defer wipe(words)
+		return checkMaterial(words, len(words)*wordSize[T]())
+	case c.seed != nil:
+		// The packing order does not change which words are zero or equal.
+		words := packWords[T](c.seed)
+		defer wipe(words)
+		return checkMaterial(words, len(c.seed))
+	default:
+		return checkMaterial(seed[:], Words*wordSize[T]())
+	}
+}
+
+// keyedSeed lays out key and nonce in a seed table as documented on
+// WithNonce.
+func keyedSeed[T uint32 | uint64](key, nonce []byte, order binary.ByteOrder) ([Words]T, error) {
+	var seed [Words]T
+	size := wordSize[T]()
+	half := Words * size / 2
+	if len(key) > half {
+		return seed, fmt.Errorf("%w: %d byte key", ErrSeedLength, len(key))
+	}
+	if len(nonce) > half-2*size {
+		return seed, fmt.Errorf("%w: %d bytes, at most %d", ErrNonceLength, len(nonce), half-2*size)
+	}
+
+	buf := make([]byte, Words*size)
+	copy(buf, key)
+	copy(buf[half:], nonce)
+	for i := range seed {
+		seed[i] = getWord[T](order, buf[i*size:])
+	}
+	seed[Words-2] = T(len(key))
+	seed[Words-1] = T(len(nonce))
+	return seed, nil
+}
+
+// next returns the next word in profile order, refilling as needed.
+func (g *generator[T]) next() T {
+	if g.destroyed {
+		panic(ErrDestroyed)
+	}
+	if g.n == 0 {
+		g.s.Refill(&g.block)
+		g.n = Words
+		if g.health != nil {
+			g.check()
+		}
+	}
+	g.n--
+	i := Words - 1 - g.n
+	if g.reverse {
+		i = g.n
+	}
+	w := g.block[i]
+	if g.s.erase {
+		// With key erasure, consumed words do not stay in memory either.
+		g.block[i] = 0
+	}
+	return w
+}
+
+// check runs the stuck-output check on a fresh block, recording a failure
+// in g.err. Only the digest of the block is kept for the next check.
+func (g *generator[T]) check() {
+	sum := blockDigest(g.digest, &g.block)
+	err := constantBlock(&g.block)
+	if sum == g.prev {
+		err = errBlockRepeated
+	}
+	if err != nil && g.err == nil {
+		g.err = err
+		g.health.fail(err)
+	}
+	g.prev = sum
+}
+
+// Read implements io.Reader.
+func (g *generator[T]) Read(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.destroyed {
+		return 0, ErrDestroyed
+	}
+	if g.err != nil {
+		return 0, g.err
+	}
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	size := wordSize[T]()
+	for n < len(p) {
+		putWord(g.order, g.tmp[:size], g.next())
+		c := copy(p[n:], g.tmp[:size])
+		g.pending = g.tmp[c:size]
+		n += c
+	}
+	if g.err != nil {
+		clear(p)
+		return 0, g.err
+	}
+	return n, nil
+}
+
+// Uint32 implements Generator.
+func (g *generator[T]) Uint32() uint32 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.pending = nil
+	w := g.next()
+	if g.err != nil {
+		panic(g.err)
+	}
+	return uint32(w)
+}
+
+// Uint64 implements Generator.
+func (g *generator[T]) Uint64() uint64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.pending = nil
+	var v uint64
+	if wordSize[T]() == 8 {
+		v = uint64(g.next())
+	} else {
+		lo, hi := uint64(g.next()), uint64(g.next())
+		if g.hiFirst {
+			lo, hi = hi, lo
+		}
+		v = hi<<32 | lo
+	}
+	if g.err != nil {
+		panic(g.err)
+	}
+	return v
+}
+
+// AddEntropy implements Generator.
+func (g *generator[T]) AddEntropy(p []byte) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.destroyed {
+		panic(ErrDestroyed)
+	}
+	size := wordSize[T]()
+	buf := make([]byte, (len(p)+size-1)/size*size)
+	copy(buf, p)
+	words := make([]T, len(buf)/size)
+	for i := range words {
+		words[i] = getWord[T](g.order, buf[i*size:])
+	}
+	g.s.Mix(words)
+	wipe(buf)
+	wipe(words)
+	wipe(g.block[:])
+	g.n = 0
+	g.pending = nil
+}
+
+// Destroy implements Generator.
+func (g *generator[T]) Destroy() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.s.Destroy()
+	wipe(g.block[:])
+	wipe(g.tmp[:])
+	wipe(g.prev[:])
+	g.n = 0
+	g.pending = nil
+	g.destroyed = true
+}
+
+// keystream is implemented by the generators of this package. It gives
+// random access to the output by copying the state at block boundaries.
+type keystream interface {
+	Generator
+	// clone returns an independent copy positioned at the same place. It
+	// must only be called at a block boundary.
+	clone() keystream
+	// skip advances the stream by whole result blocks without producing
+	// output. It must only be called at a block boundary.
+	skip(blocks int64)
+	// blockBytes returns the number of output bytes per result block.
+	blockBytes() int
+}
+
+func (g *generator[T]) clone() keystream {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.s.mu.Lock()
+	defer g.s.mu.Unlock()
+
+	c := &generator[T]{layout: g.layout}
+	c.s.m, c.s.a, c.s.b, c.s.c = g.s.m, g.s.a, g.s.b, g.s.c
+	c.s.ct, c.s.erase = g.s.ct, g.s.erase
+	c.s.init, c.s.custom = g.s.init, g.s.custom
+	return c
+}
+
+func (g *generator[T]) skip(blocks int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for range blocks {
+		g.s.Refill(&g.block)
+	}
+}
+
+func (g *generator[T]) blockBytes() int {
+	return Words * wordSize[T]()
+}
+
+// Width implements Generator.
+func (g *generator[T]) Width() int {
+	return int(paramsOf[T]().bits)
+}
+
+// wordSize returns the size of T in bytes.
+func wordSize[T uint32 | uint64]() int {
+	return int(paramsOf[T]().bits / 8)
+}
+
+// getWord decodes a word of type T from the start of b.
+func getWord[T uint32 | uint64](order binary.ByteOrder, b []byte) T {
+	if wordSize[T]() == 4 {
+		return T(order.Uint32(b))
+	}
+	return T(order.Uint64(b))
+}
+
+// putWord encodes w at the start of b.
+func putWord[T uint32 | uint64](order binary.ByteOrder, b []byte, w T) {
+	if wordSize[T]() == 4 {
+		order.PutUint32(b, uint32(w))
+		return
+	}
+	order.PutUint64(b, uint64(w))
+}
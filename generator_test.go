@@ -0,0 +1,165 @@
+package isaac
+
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratorDefault(t *testing.T) {
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	require.Equal(t, 64, g.Width())
+
+	var r [Words]uint64
+	New64().Refill(&r)
+	for _, w := range r {
+		require.Equal(t, w, g.Uint64())
+	}
+}
+
+func TestGeneratorProfiles(t *testing.T) {
+	var r [Words]uint32
+	New32().Refill(&r)
+
+	g, err := NewGenerator(WithWidth(32), WithProfile(Jenkins))
+	require.NoError(t, err)
+	require.Equal(t, r[Words-1], g.Uint32())
+
+	g, err = NewGenerator(WithWidth(32), WithProfile(Rust))
+	require.NoError(t, err)
+	require.Equal(t, uint64(r[Words-2])<<32|uint64(r[Words-1]), g.Uint64())
+
+	g, err = NewGenerator(WithWidth(32), WithProfile(Java))
+	require.NoError(t, err)
+	require.Equal(t, uint64(r[Words-1])<<32|uint64(r[Words-2]), g.Uint64())
+
+	p, err := ParseProfile("java")
+	require.NoError(t, err)
+	require.Equal(t, Java, p)
+	_, err = ParseProfile("python")
+	require.ErrorIs(t, err, ErrProfile)
+}
+
+func TestGeneratorRead(t *testing.T) {
+	var r [Words]uint64
+	New64().Refill(&r)
+	want := make([]byte, 0, Words*8)
+	for _, w := range r {
+		want = binary.BigEndian.AppendUint64(want, w)
+	}
+
+	g, err := NewGenerator(WithByteOrder(binary.BigEndian))
+	require.NoError(t, err)
+	got := make([]byte, Words*8)
+	// Read in uneven pieces to exercise partially read words.
+	for off := 0; off < len(got); {
+		n, err := g.Read(got[off:min(off+13, len(got))])
+		require.NoError(t, err)
+		off += n
+	}
+	require.Equal(t, want, got)
+}
+
+func TestGeneratorSeed(t *testing.T) {
+	seed := make([]byte, Words*4)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	var words [Words]uint32
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(seed[i*4:])
+	}
+	s := New32()
+	s.Seed(words)
+	var r [Words]uint32
+	s.Refill(&r)
+
+	for _, opt := range []Option{WithSeed(seed), WithSeed(words[:]), WithEntropy(bytes.NewReader(seed))} {
+		g, err := NewGenerator(WithWidth(32), opt)
+		require.NoError(t, err)
+		require.Equal(t, r[0], g.Uint32())
+	}
+
+	v := InitValues(WithBase[uint32](1))
+	s.Seed([Words]uint32{}, v[:]...)
+	s.Refill(&r)
+	g, err := NewGenerator(WithInitValues(v[:]...))
+	require.NoError(t, err)
+	require.Equal(t, 32, g.Width())
+	require.Equal(t, r[0], g.Uint32())
+}
+
+func TestGeneratorErrors(t *testing.T) {
+	for _, tc := range []struct {
+		opts []Option
+		err  error
+	}{
+		{[]Option{WithWidth(16)}, ErrWidth},
+		{[]Option{WithWidth(64), WithSeed([]uint32{1})}, ErrWidth},
+		{[]Option{WithSeed([]uint32{1}), WithInitValues(make([]uint64, 8)...)}, ErrWidth},
+		{[]Option{WithInitValues[uint64](1, 2, 3)}, ErrInitValues},
+		{[]Option{WithSeed(make([]byte, Words*8+1))}, ErrSeedLength},
+		{[]Option{WithSeed(make([]uint64, Words+1))}, ErrSeedLength},
+		{[]Option{WithSeed([]byte{1}), WithEntropy(bytes.NewReader(nil))}, ErrSeedSources},
+		{[]Option{WithProfile(Profile(42))}, ErrProfile},
+	} {
+		_, err := NewGenerator(tc.opts...)
+		require.ErrorIs(t, err, tc.err)
+	}
+
+	_, err := NewGenerator(WithEntropy(bytes.NewReader(make([]byte, 10))))
+	require.Error(t, err)
+}
+
+// TestGeneratorWeChat checks the WeChat profile against the keystream
+// layout of TestWxIsaac64.
+func TestGeneratorWeChat(t *testing.T) {
+	var r [Words]uint64
+	var seeds [Words]uint64
+	seeds[0] = 12312312
+	s := New64()
+	s.Seed(seeds)
+	s.Refill(&r)
+	want := make([]byte, 0, Words*8)
+	for _, w := range r {
+		want = binary.LittleEndian.AppendUint64(want, w)
+	}
+	slices.Reverse(want)
+
+	g, err := NewGenerator(WithSeed(seeds[:1]), WithProfile(WeChat))
+	require.NoError(t, err)
+	got := make([]byte, len(want))
+	_, _ = g.Read(got)
+	require.Equal(t, want, got)
+	require.Len(t, Profiles(), 5)
+}
+
+func TestGeneratorConstantTime(t *testing.T) {
+	for _, width := range []int{32, 64} {
+		g1, err := NewGenerator(WithWidth(width), WithSeed([]byte("key")))
+		require.NoError(t, err)
+		g2, err := NewGenerator(WithWidth(width), WithSeed([]byte("key")), WithConstantTime())
+		require.NoError(t, err)
+		b1, b2 := make([]byte, 5000), make([]byte, 5000)
+		_, _ = g1.Read(b1)
+		_, _ = g2.Read(b2)
+		require.Equal(t, b1, b2)
+	}
+}
+
+func TestGeneratorKeyErasure(t *testing.T) {
+	g1, err := NewGenerator(WithSeed([]byte("key")))
+	require.NoError(t, err)
+	g2, err := NewGenerator(WithSeed([]byte("key")), WithKeyErasure())
+	require.NoError(t, err)
+
+	for range Words {
+		require.Equal(t, g1.Uint64(), g2.Uint64())
+	}
+	require.Equal(t, [Words]uint64{}, g2.(*generator[uint64]).block)
+	require.NotEqual(t, g1.Uint64(), g2.Uint64())
+}
@@ -0,0 +1,228 @@
+package isaac
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math"
+	"slices"
+	"sync"
+)
+
+// Errors reported by health tests. All of them wrap ErrHealth.
+var (
+	ErrHealth             = errors.New("isaac: health test failed")
+	ErrRepetitionCount    = fmt.Errorf("%w: repetition count", ErrHealth)
+	ErrAdaptiveProportion = fmt.Errorf("%w: adaptive proportion", ErrHealth)
+	ErrStuckOutput        = fmt.Errorf("%w: stuck output", ErrHealth)
+
+	errBlockRepeated = fmt.Errorf("%w: result block repeated", ErrStuckOutput)
+)
+
+// Parameters of the NIST SP 800-90B continuous health tests: a false
+// positive probability of 2^-healthAlpha per test and the adaptive
+// proportion window for non-binary samples.
+const (
+	healthAlpha = 20
+	aptWindow   = 512
+)
+
+// HealthTest runs the SP 800-90B Repetition Count Test and Adaptive
+// Proportion Test over entropy input, taking each byte as one sample. A
+// failure is sticky: every later Write returns the same error until Reset,
+// so a broken source cannot quietly recover mid-seed. It is safe for
+// concurrent use.
+type HealthTest struct {
+	// OnFailure, if set, is called with every failure, including stuck
+	// output detected by generators created with WithHealthTest.
+	OnFailure func(error)
+
+	mu        sync.Mutex
+	rctCutoff int
+	aptCutoff int
+	err       error
+
+	rctLast  byte // sample repeated in the current run
+	rctRun   int  // length of the current run, 0 before the first sample
+	aptFirst byte // first sample of the current window
+	aptCount int  // occurrences of aptFirst in the window
+	aptSeen  int  // samples of the window seen, 0 before the window starts
+}
+
+// NewHealthTest creates health tests for a source claimed to deliver
+// minEntropy bits of min-entropy per byte, between 0 and 8. The cutoffs
+// are derived from that claim as SP 800-90B sections 4.4.1 and 4.4.2
+// specify.
+func NewHealthTest(minEntropy float64) (*HealthTest, error) {
+	if !(minEntropy > 0 && minEntropy <= 8) {
+		return nil, fmt.Errorf("isaac: min-entropy must be in (0, 8] bits per byte, got %v", minEntropy)
+	}
+	return &HealthTest{
+		rctCutoff: 1 + int(math.Ceil(healthAlpha/minEntropy)),
+		aptCutoff: 1 + critBinom(aptWindow, math.Exp2(-minEntropy), healthAlpha),
+	}, nil
+}
+
+// critBinom returns the smallest k for which the binomial distribution
+// with n trials and success probability p has a CDF of at least
+// 1-2^-alpha, capped at n.
+func critBinom(n int, p float64, alpha int) int {
+	target := 1 - math.Exp2(-float64(alpha))
+	// Work in log space: (1-p)^n underflows for small min-entropy.
+	logPMF := float64(n) * math.Log1p(-p)
+	cdf := 0.0
+	for k := 0; k < n; k++ {
+		cdf += math.Exp(logPMF)
+		if cdf >= target {
+			return k
+		}
+		logPMF += math.Log(float64(n-k)) - math.Log(float64(k+1)) + math.Log(p) - math.Log1p(-p)
+	}
+	return n
+}
+
+// Cutoffs returns the cutoffs of the Repetition Count Test and of the
+// Adaptive Proportion Test.
+func (h *HealthTest) Cutoffs() (rct, apt int) {
+	return h.rctCutoff, h.aptCutoff
+}
+
+// Write feeds samples to both tests. It returns the number of samples
+// accepted before the first failure.
+func (h *HealthTest) Write(p []byte) (int, error) {
+	h.mu.Lock()
+	if err := h.err; err != nil {
+		h.mu.Unlock()
+		return 0, err
+	}
+	for i, x := range p {
+		if err := h.sample(x); err != nil {
+			h.err = err
+			h.mu.Unlock()
+			h.report(err)
+			return i, err
+		}
+	}
+	h.mu.Unlock()
+	return len(p), nil
+}
+
+// sample runs both tests on one sample. The caller holds mu.
+func (h *HealthTest) sample(x byte) error {
+	if h.rctRun > 0 && x == h.rctLast {
+		h.rctRun++
+		if h.rctRun >= h.rctCutoff {
+			return fmt.Errorf("%w: byte %#02x repeated %d times", ErrRepetitionCount, x, h.rctRun)
+		}
+	} else {
+		h.rctLast, h.rctRun = x, 1
+	}
+
+	switch {
+	case h.aptSeen == 0:
+		h.aptFirst, h.aptCount = x, 1
+	case x == h.aptFirst:
+		h.aptCount++
+		if h.aptCount >= h.aptCutoff {
+			return fmt.Errorf("%w: byte %#02x seen %d times in %d", ErrAdaptiveProportion, x, h.aptCount, aptWindow)
+		}
+	}
+	h.aptSeen = (h.aptSeen + 1) % aptWindow
+	return nil
+}
+
+// report calls OnFailure, if set, without holding mu.
+func (h *HealthTest) report(err error) {
+	if h.OnFailure != nil {
+		h.OnFailure(err)
+	}
+}
+
+// fail records a failure found outside the entropy tests.
+func (h *HealthTest) fail(err error) {
+	h.mu.Lock()
+	if h.err == nil {
+		h.err = err
+	}
+	h.mu.Unlock()
+	h.report(err)
+}
+
+// Err returns the recorded failure, or nil.
+func (h *HealthTest) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.err
+}
+
+// Reset clears a failure and restarts both tests.
+func (h *HealthTest) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.err = nil
+	h.rctRun, h.aptSeen = 0, 0
+}
+
+// NewHealthReader returns a reader that passes everything read from r
+// through h. Once h fails, Read returns 0 and the failure, discarding the
+// bytes read, so the data never reaches a seed.
+func NewHealthReader(r io.Reader, h *HealthTest) io.Reader {
+	return &healthReader{r: r, h: h}
+}
+
+type healthReader struct {
+	r io.Reader
+	h *HealthTest
+}
+
+func (hr *healthReader) Read(p []byte) (int, error) {
+	n, err := hr.r.Read(p)
+	if _, herr := hr.h.Write(p[:n]); herr != nil {
+		clear(p[:n])
+		return 0, herr
+	}
+	return n, err
+}
+
+// StuckBlock reports ErrStuckOutput when cur repeats the previous result
+// block prev or consists of a single repeated word, both signs of a broken
+// generator rather than of chance.
+func StuckBlock[T Word](prev, cur *[Words]T) error {
+	if *prev == *cur {
+		return errBlockRepeated
+	}
+	return constantBlock(cur)
+}
+
+// constantBlock reports ErrStuckOutput when cur consists of a single
+// repeated word.
+func constantBlock[T Word](cur *[Words]T) error {
+	if !slices.ContainsFunc(cur[1:], func(w T) bool { return w != cur[0] }) {
+		return fmt.Errorf("%w: result block of one repeated word %#x", ErrStuckOutput, cur[0])
+	}
+	return nil
+}
+
+// blockDigest returns the SHA-256 digest of b and a trailing block of
+// zeros, which leaves no output in the internal buffer of h. Generators
+// compare digests rather than keeping a copy of the previous block, which
+// would defeat key erasure.
+func blockDigest[T Word](h hash.Hash, b *[Words]T) [sha256.Size]byte {
+	var buf [8]byte
+	size := int(paramsOf[T]().bits / 8)
+	h.Reset()
+	for _, w := range b {
+		binary.LittleEndian.PutUint64(buf[:], uint64(w))
+		h.Write(buf[:size])
+	}
+	wipe(buf[:])
+	h.Write(make([]byte, h.BlockSize()))
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum
+}
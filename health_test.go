@@ -0,0 +1,169 @@
+package isaac
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestHealthCutoffs checks the cutoffs against SP 800-90B, Table 2 and the
+// RCT formula, for alpha = 2^-20 and a window of 512.
+func TestHealthCutoffs(t *testing.T) {
+	for _, c := range []struct {
+		h        float64
+		rct, apt int
+	}{
+		{0.5, 41, 410},
+		{1, 21, 311},
+		{2, 11, 177},
+		{4, 6, 62},
+		{8, 4, 13},
+	} {
+		h, err := NewHealthTest(c.h)
+		require.NoError(t, err)
+		rct, apt := h.Cutoffs()
+		require.Equal(t, c.rct, rct, c.h)
+		require.Equal(t, c.apt, apt, c.h)
+	}
+
+	for _, bad := range []float64{0, -1, 8.5} {
+		_, err := NewHealthTest(bad)
+		require.Error(t, err)
+	}
+}
+
+func TestRepetitionCount(t *testing.T) {
+	h, err := NewHealthTest(8)
+	require.NoError(t, err)
+	var failures []error
+	h.OnFailure = func(err error) { failures = append(failures, err) }
+
+	n, err := h.Write([]byte{1, 2, 2, 2, 3})
+	require.NoError(t, err)
+	require.Equal(t, 5, n)
+	n, err = h.Write([]byte{4, 5, 5, 5, 5, 6})
+	require.ErrorIs(t, err, ErrRepetitionCount)
+	require.ErrorIs(t, err, ErrHealth)
+	require.Equal(t, 4, n)
+	require.Len(t, failures, 1)
+
+	// Failures are sticky until Reset.
+	_, err = h.Write([]byte{7})
+	require.ErrorIs(t, err, ErrRepetitionCount)
+	require.ErrorIs(t, h.Err(), ErrRepetitionCount)
+	require.Len(t, failures, 1)
+	h.Reset()
+	require.NoError(t, h.Err())
+	_, err = h.Write([]byte{5, 5, 5})
+	require.NoError(t, err)
+}
+
+func TestAdaptiveProportion(t *testing.T) {
+	h, err := NewHealthTest(8)
+	require.NoError(t, err)
+	_, apt := h.Cutoffs()
+
+	// The first sample of a window reappearing apt-1 times in total passes,
+	// never repeated back to back.
+	window := make([]byte, aptWindow)
+	for i := range window {
+		window[i] = byte(i%251) + 1
+	}
+	for i := 0; i < apt-1; i++ {
+		window[i*2] = 0
+	}
+	_, err = h.Write(window)
+	require.NoError(t, err)
+
+	window[(apt-1)*2] = 0
+	_, err = h.Write(window)
+	require.ErrorIs(t, err, ErrAdaptiveProportion)
+}
+
+func TestHealthRandom(t *testing.T) {
+	h, err := NewHealthTest(7)
+	require.NoError(t, err)
+	buf := make([]byte, 1<<16)
+	_, err = rand.Read(buf)
+	require.NoError(t, err)
+	_, err = h.Write(buf)
+	require.NoError(t, err)
+}
+
+func TestHealthReader(t *testing.T) {
+	h, err := NewHealthTest(8)
+	require.NoError(t, err)
+	_, err = NewGenerator(WithEntropy(bytes.NewReader(make([]byte, 4096))), WithHealthTest(h))
+	require.ErrorIs(t, err, ErrRepetitionCount)
+
+	h.Reset()
+	_, err = NewGenerator(WithEntropy(rand.Reader), WithHealthTest(h))
+	require.NoError(t, err)
+}
+
+func TestStuckBlock(t *testing.T) {
+	var prev, cur [Words]uint32
+	for i := range cur {
+		cur[i] = uint32(i)
+	}
+	require.NoError(t, StuckBlock(&prev, &cur))
+	require.ErrorIs(t, StuckBlock(&cur, &cur), ErrStuckOutput)
+	for i := range cur {
+		cur[i] = 7
+	}
+	require.ErrorIs(t, StuckBlock(&prev, &cur), ErrStuckOutput)
+}
+
+func TestGeneratorStuckOutput(t *testing.T) {
+	h, err := NewHealthTest(8)
+	require.NoError(t, err)
+	var reported error
+	h.OnFailure = func(err error) { reported = err }
+
+	g, err := NewGenerator(WithHealthTest(h))
+	require.NoError(t, err)
+	_, err = g.Read(make([]byte, Words*8))
+	require.NoError(t, err)
+
+	// Make the next block look like a repeat of the previous one.
+	var next [Words]uint64
+	s := New[uint64]()
+	s.Refill(&next)
+	s.Refill(&next)
+	gg := g.(*generator[uint64])
+	gg.prev = blockDigest(gg.digest, &next)
+
+	out := make([]byte, 16)
+	n, err := g.Read(out)
+	require.ErrorIs(t, err, ErrStuckOutput)
+	require.Zero(t, n)
+	require.Equal(t, make([]byte, 16), out)
+	require.ErrorIs(t, reported, ErrStuckOutput)
+	require.PanicsWithError(t, err.Error(), func() { g.Uint64() })
+	require.PanicsWithError(t, err.Error(), func() { g.Uint32() })
+}
+
+// TestHealthKeyErasure checks that the stuck-output check keeps no output
+// in memory that key erasure has removed.
+func TestHealthKeyErasure(t *testing.T) {
+	h, err := NewHealthTest(8)
+	require.NoError(t, err)
+	g, err := NewGenerator(WithHealthTest(h), WithKeyErasure())
+	require.NoError(t, err)
+	gg := g.(*generator[uint64])
+
+	seen := make(map[uint64]bool)
+	for range Words + Words/2 {
+		seen[g.Uint64()] = true
+	}
+	for _, w := range gg.block {
+		require.False(t, seen[w] && w != 0, "consumed word %#x left in the block", w)
+	}
+	for i := 0; i+8 <= len(gg.prev); i++ {
+		require.False(t, seen[binary.LittleEndian.Uint64(gg.prev[i:])], "output word in the digest")
+	}
+	require.NoError(t, h.Err())
+}
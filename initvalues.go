@@ -0,0 +1,73 @@
+package isaac
+
+import "fmt"
+
+// goldenRatio is the fractional part of the golden ratio scaled to 64 bits,
+// the constant Jenkins' randinit starts from.
+const goldenRatio = 0x9e3779b97f4a7c13
+
+// GoldenRatio returns the golden ratio constant truncated to the width of T:
+// 0x9e3779b9 for uint32 and 0x9e3779b97f4a7c13 for uint64.
+func GoldenRatio[T Word]() T {
+	return T(uint64(goldenRatio) >> (64 - paramsOf[T]().bits))
+}
+
+// initConfig holds the settings InitValues derives from.
+type initConfig[T Word] struct {
+	base   [8]T
+	rounds int
+}
+
+// InitOption customises the derivation done by InitValues.
+type InitOption[T Word] func(*initConfig[T])
+
+// WithBase replaces the golden ratio as starting point of the derivation.
+// A single value is used for all eight words; otherwise exactly eight
+// values must be given.
+func WithBase[T Word](base ...T) InitOption[T] {
+	if len(base) != 1 && len(base) != 8 {
+		panic("isaac: need 1 or 8 base values")
+	}
+	return func(c *initConfig[T]) {
+		if len(base) == 1 {
+			for i := range c.base {
+				c.base[i] = base[0]
+			}
+			return
+		}
+		copy(c.base[:], base)
+	}
+}
+
+// WithRounds sets how many times the base is scrambled through mix.
+func WithRounds[T Word](rounds int) InitOption[T] {
+	if rounds < 0 {
+		panic(fmt.Sprintf("isaac: invalid mix round count %d", rounds))
+	}
+	return func(c *initConfig[T]) {
+		c.rounds = rounds
+	}
+}
+
+// InitValues derives the eight initial values Seed starts mixing from.
+// By default each word is set to GoldenRatio and the eight words are
+// scrambled four times through mix (mix32 and mix64 for the standard
+// widths), which is what Jenkins' randinit does and yields the constants
+// hard-coded in the coreutils version. The result can be passed to Seed:
+//
+//	s.Seed(seed, isaac.InitValues(isaac.WithBase[uint64](base))[:]...)
+func InitValues[T Word](opts ...InitOption[T]) [8]T {
+	c := initConfig[T]{rounds: 4}
+	for i := range c.base {
+		c.base[i] = GoldenRatio[T]()
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	v := c.base
+	for range c.rounds {
+		v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7] = mix(v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7])
+	}
+	return v
+}
@@ -2,7 +2,8 @@
 package isaac
 
 import (
-	"math"
+	"crypto/subtle"
+	"math/bits"
 	"sync"
 )
 
@@ -13,18 +14,69 @@ const (
 	WordsLog = 8
 )
 
+// Word is the set of word types an ISAAC instance can be built on.
+// uint32 and uint64 give the standard ISAAC and ISAAC-64 generators,
+// uint8 and uint16 give reduced-width toy variants meant for cryptanalysis
+// and cycle studies only. With the full Words table the second state lookup
+// of those narrow variants reaches only part of the table; use Toy for a
+// faithful scaled-down kernel.
+type Word interface {
+	uint8 | uint16 | uint32 | uint64
+}
+
+// params holds the word-size dependent constants of the ISAAC kernel.
+type params struct {
+	bits  uint    // word size in bits
+	shift uint    // log2 of the word size in bytes, used by ind
+	step  [4]uint // shifts of the four refill steps
+	mix   [8]uint // shifts of the eight xor-shift rounds of mix
+}
+
+// The 32-bit and 64-bit tables are the ones from the C version. The toy
+// tables are not part of any published specification: they scale the
+// 32-bit shifts down so every shift stays below the word size.
+var (
+	params8  = params{bits: 8, shift: 0, step: [4]uint{3, 2, 1, 4}, mix: [8]uint{3, 1, 2, 4, 3, 1, 2, 2}}
+	params16 = params{bits: 16, shift: 1, step: [4]uint{7, 3, 1, 8}, mix: [8]uint{6, 1, 4, 8, 5, 2, 4, 5}}
+	params32 = params{bits: 32, shift: 2, step: [4]uint{13, 6, 2, 16}, mix: [8]uint{11, 2, 8, 16, 10, 4, 8, 9}}
+	params64 = params{bits: 64, shift: 3, step: [4]uint{21, 5, 12, 33}, mix: [8]uint{9, 9, 23, 15, 14, 20, 17, 14}}
+)
+
+// paramsOf returns the kernel constants for the word type T.
+func paramsOf[T Word]() *params {
+	var w T
+	switch any(w).(type) {
+	case uint8:
+		return &params8
+	case uint16:
+		return &params16
+	case uint32:
+		return &params32
+	default:
+		return &params64
+	}
+}
+
 // ISAAC struct using generic type
-type ISAAC[T uint32 | uint64] struct {
-	m  [Words]T
-	r  []T
-	a  T
-	b  T
-	c  T
-	mu sync.Mutex // mutex for concurrency safety
+type ISAAC[T Word] struct {
+	m         [Words]T
+	r         [Words]T // results handed out by Rand
+	n         int      // unread results left in r
+	a         T
+	b         T
+	c         T
+	ct        bool // constant-time state lookups
+	erase     bool // fast key erasure after each refill
+	init      [8]T // initial values given to the last Seed, reused by Mix
+	custom    bool // init holds custom initial values
+	destroyed bool
+	mu        sync.Mutex // mutex for concurrency safety
 }
 
-// New creates a new ISAAC instance
-func New[T uint32 | uint64]() *ISAAC[T] {
+// New creates a new ISAAC instance seeded with zeros, the seed of the
+// published test vectors. Seed it before use, checking the seed with
+// CheckSeed if it does not come from a trusted entropy source.
+func New[T Word]() *ISAAC[T] {
 	var s ISAAC[T]
 	s.Seed([Words]T{})
 	return &s
@@ -35,88 +87,25 @@ func (s *ISAAC[T]) Seed(seed [Words]T, initValues ...T) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
 	if len(initValues) > 0 && len(initValues) != 8 {
 		panic("isaac: need exactly 8 initial values")
 	}
 
 	// Use the same initial values as the C version
-	var a, b, c, d, e, f, g, h T
-	switch any(a).(type) {
-	case uint32:
-		var a32, b32, c32, d32, e32, f32, g32, h32 uint32
-		if len(initValues) == 8 {
-			a32 = uint32(initValues[0])
-			b32 = uint32(initValues[1])
-			c32 = uint32(initValues[2])
-			d32 = uint32(initValues[3])
-			e32 = uint32(initValues[4])
-			f32 = uint32(initValues[5])
-			g32 = uint32(initValues[6])
-			h32 = uint32(initValues[7])
-		} else {
-			a32 = uint32(0x1367df5a)
-			b32 = uint32(0x95d90059)
-			c32 = uint32(0xc3163e4b)
-			d32 = uint32(0x0f421ad8)
-			e32 = uint32(0xd92a4a78)
-			f32 = uint32(0xa51a3c49)
-			g32 = uint32(0xc4efea1b)
-			h32 = uint32(0x30609119)
-		}
-		a, b, c, d, e, f, g, h = T(a32), T(b32), T(c32), T(d32), T(e32), T(f32), T(g32), T(h32)
-	case uint64:
-		var a64, b64, c64, d64, e64, f64, g64, h64 uint64
-		if len(initValues) == 8 {
-			a64 = uint64(initValues[0])
-			b64 = uint64(initValues[1])
-			c64 = uint64(initValues[2])
-			d64 = uint64(initValues[3])
-			e64 = uint64(initValues[4])
-			f64 = uint64(initValues[5])
-			g64 = uint64(initValues[6])
-			h64 = uint64(initValues[7])
-		} else {
-			a64 = uint64(0x647c4677a2884b7c)
-			b64 = uint64(0xb9f8b322c73ac862)
-			c64 = uint64(0x8c0ea5053d4712a0)
-			d64 = uint64(0xb29b2e824a595524)
-			e64 = uint64(0x82f053db8355e0ce)
-			f64 = uint64(0x48fe4a0fa5a09315)
-			g64 = uint64(0xae985bf2cbfc89ed)
-			h64 = uint64(0x98f5704f6c44c0ab)
-		}
-		a, b, c, d, e, f, g, h = T(a64), T(b64), T(c64), T(d64), T(e64), T(f64), T(g64), T(h64)
+	v := InitValues[T]()
+	if len(initValues) == 8 {
+		copy(v[:], initValues)
 	}
+	s.init, s.custom = v, len(initValues) == 8
 
 	// Initialize m array
-	for i := 0; i < Words; i++ {
-		s.m[i] = seed[i]
-	}
-
-	// Mix S->m so that every part of the seed affects every part of the state
-	// Two rounds of mixing
-	for range [2]struct{}{} {
-		for i := 0; i < Words; i += 8 {
-			a += s.m[i]
-			b += s.m[i+1]
-			c += s.m[i+2]
-			d += s.m[i+3]
-			e += s.m[i+4]
-			f += s.m[i+5]
-			g += s.m[i+6]
-			h += s.m[i+7]
-			a, b, c, d, e, f, g, h = mix(a, b, c, d, e, f, g, h)
-			s.m[i] = a
-			s.m[i+1] = b
-			s.m[i+2] = c
-			s.m[i+3] = d
-			s.m[i+4] = e
-			s.m[i+5] = f
-			s.m[i+6] = g
-			s.m[i+7] = h
-		}
-	}
+	s.m = seed
+	seedState(s.m[:], v)
 
+	s.n = 0
 	s.a = 0
 	s.b = 0
 	s.c = 0
@@ -127,78 +116,71 @@ func (s *ISAAC[T]) Refill(r *[Words]T) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	a := s.a
-	b := s.b + (s.c + 1)
-	s.c++
-
-	HALF := Words / 2
-
-	// isaac_step corresponds to the ISAAC_STEP macro in C
-	step := func(i int, off int, mix T) {
-		switch any(a).(type) {
-		case uint32:
-			a = (a ^ mix) + s.m[off+i]
-		case uint64:
-			a = (0 ^ mix) + s.m[off+i]
-		}
-		x := s.m[i]
-		y := ind(s.m, x) + a + b
-		s.m[i] = y
-		b = just(ind(s.m, y>>WordsLog) + x)
-		r[i] = b
+	if s.destroyed {
+		panic(ErrDestroyed)
 	}
+	s.refill(r)
+}
 
-	right := 33
-	// First half
-	for i := 0; i < HALF; i += 4 {
-		switch any(a).(type) {
-		case uint32:
-			// step1: a = (a << 13)
-			step(i, HALF, a<<13)
-			// step2: a = (a >> 6)
-			step(i+1, HALF, a>>6)
-			// step3: a = (a << 2)
-			step(i+2, HALF, a<<2)
-			// step4: a = (a >> 16)
-			step(i+3, HALF, a>>16)
-		case uint64:
-			// step1: a = ^ (a ^ (a << 21))
-			step(i, HALF, ^(a ^ (a << 21)))
-			// step2: a = a ^ (a >> 5)
-			step(i+1, HALF, a^(just(a)>>5))
-			// step3: a = a ^ (a << 12)
-			step(i+2, HALF, a^(a<<12))
-			// step4: a = a ^ (a >> 33) right is 33
-			step(i+3, HALF, a^(just(a)>>right))
-		}
+// refill is Refill without locking, for callers already holding mu.
+func (s *ISAAC[T]) refill(r *[Words]T) {
+	s.refillBlock(r)
+	if s.erase {
+		s.eraseKey()
 	}
+}
 
-	// Second half
-	for i := HALF; i < Words; i += 4 {
-		switch any(a).(type) {
-		case uint32:
-			// step1: a = (a << 13)
-			step(i, -HALF, a<<13)
-			// step2: a = (a >> 6)
-			step(i+1, -HALF, a>>6)
-			// step3: a = (a << 2)
-			step(i+2, -HALF, a<<2)
-			// step4: a = (a >> 16)
-			step(i+3, -HALF, a>>16)
-		case uint64:
-			// step1: a = ^ (a ^ (a << 21))
-			step(i, -HALF, ^(a ^ (a << 21)))
-			// step2: a = a ^ (a >> 5)
-			step(i+1, -HALF, a^(just(a)>>5))
-			// step3: a = a ^ (a << 12)
-			step(i+2, -HALF, a^(a<<12))
-			// step4: a = a ^ (a >> 33) right is 33
-			step(i+3, -HALF, a^(just(a)>>right))
-		}
+// refillBlock runs one round of the kernel selected by SetConstantTime.
+func (s *ISAAC[T]) refillBlock(r *[Words]T) {
+	if s.ct {
+		s.a, s.b, s.c = refillCT(s.m[:], r[:], s.a, s.b, s.c)
+		return
 	}
+	s.a, s.b, s.c = refill(s.m[:], r[:], s.a, s.b, s.c)
+}
 
-	s.a = a
-	s.b = b
+// eraseKey replaces the state table with a result block that is never
+// output, mixed as Seed mixes a seed.
+func (s *ISAAC[T]) eraseKey() {
+	var k [Words]T
+	s.refillBlock(&k)
+	s.m = k
+	seedState(s.m[:], InitValues[T]())
+	wipe(k[:])
+}
+
+// SetKeyErasure turns fast key erasure on or off. With it on, every refill
+// is followed by a second, hidden refill whose results replace the whole
+// state table and are mixed as by Seed, and Rand zeroes each result once
+// it is returned. The output then differs from the standard ISAAC stream,
+// so the default stays off and coreutils-compatible.
+//
+// This gives backtracking resistance: the state left in memory after a
+// block was produced depends on the state that produced it only through
+// the hidden block, the a, b and c registers and the refill count. An
+// attacker who reads the memory can run the generator forward, but to
+// recompute earlier output must recover an ISAAC state from one of its
+// result blocks, which is the state-recovery problem ISAAC's security
+// rests on. Results the caller keeps from Refill are not covered. Refills
+// cost about three times as much.
+func (s *ISAAC[T]) SetKeyErasure(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.erase = on
+}
+
+// SetConstantTime switches the state lookups of Refill and Rand between the
+// default indexed reads and constant-time reads that scan the whole state
+// table with masked selects. The secret-dependent indexes of ISAAC otherwise
+// leak through cache timing when it is used as a cipher; the scans make
+// refills about two orders of magnitude slower (see BenchmarkRefill). The
+// output is the same in both modes.
+func (s *ISAAC[T]) SetConstantTime(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ct = on
 }
 
 // Rand returns the next random number
@@ -206,93 +188,217 @@ func (s *ISAAC[T]) Rand() T {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if len(s.r) == 0 {
-		var r [Words]T
-		s.Refill(&r)
-		s.r = r[:]
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
+	if s.n == 0 {
+		s.refill(&s.r)
+		s.n = Words
 	}
-	result := s.r[0]
-	s.r = s.r[1:]
+	result := s.r[Words-s.n]
+	if s.erase {
+		s.r[Words-s.n] = 0
+	}
+	s.n--
 	return result
 }
 
-// Generic implementation of ind function
-func ind[T uint32 | uint64](m [Words]T, x T) T {
-	var shift T
-	switch any(x).(type) {
-	case uint32:
-		shift = 2
-	case uint64:
-		shift = 3
+// Destroy zeroes the state table, the results buffered for Rand and the
+// registers. Any later call to Seed, Refill or Rand panics with
+// ErrDestroyed. Results already returned by Refill are the caller's to wipe.
+func (s *ISAAC[T]) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wipe(s.m[:])
+	wipe(s.r[:])
+	wipe(s.init[:])
+	s.n = 0
+	s.a, s.b, s.c = 0, 0, 0
+	s.destroyed = true
+}
+
+// seedState mixes the state table m in place, starting from the initial
+// values v, so that every part of the seed affects every part of the state.
+// len(m) must be a multiple of 8.
+func seedState[T Word](m []T, v [8]T) {
+	a, b, c, d, e, f, g, h := v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7]
+
+	// Two rounds of mixing
+	for range [2]struct{}{} {
+		for i := 0; i < len(m); i += 8 {
+			a += m[i]
+			b += m[i+1]
+			c += m[i+2]
+			d += m[i+3]
+			e += m[i+4]
+			f += m[i+5]
+			g += m[i+6]
+			h += m[i+7]
+			a, b, c, d, e, f, g, h = mix(a, b, c, d, e, f, g, h)
+			m[i] = a
+			m[i+1] = b
+			m[i+2] = c
+			m[i+3] = d
+			m[i+4] = e
+			m[i+5] = f
+			m[i+6] = g
+			m[i+7] = h
+		}
 	}
-	return m[(x&((Words-1)<<shift))>>shift]
 }
 
-// Generic implementation of just function
-func just[T uint32 | uint64](a T) T {
-	switch v := any(a).(type) {
-	case uint32:
-		return T(v & math.MaxUint32)
-	case uint64:
-		return T(v & math.MaxUint64)
-	default:
-		return a
+// refill runs one round of isaac_refill over the state table m, writing
+// len(m) results to r, and returns the updated a, b and c registers.
+// len(m) must be a power of two and at least 8.
+func refill[T Word](m, r []T, a, b, c T) (T, T, T) {
+	p := paramsOf[T]()
+	n := len(m)
+	half := n / 2
+	log := uint(bits.TrailingZeros(uint(n)))
+
+	b += c + 1
+	c++
+
+	// isaac_step corresponds to the ISAAC_STEP macro in C
+	step := func(i int, off int, mix T) {
+		a = mix + m[off+i]
+		x := m[i]
+		y := ind(m, x, p.shift) + a + b
+		m[i] = y
+		b = ind(m, y>>log, p.shift) + x
+		r[i] = b
+	}
+
+	for i := 0; i < n; i += 4 {
+		off := half
+		if i >= half {
+			off = -half
+		}
+		if p.bits == 64 {
+			// step1: a = ~(a ^ (a << 21))
+			step(i, off, ^(a ^ (a << p.step[0])))
+		} else {
+			// step1: a = a ^ (a << 13)
+			step(i, off, a^(a<<p.step[0]))
+		}
+		// step2: a = a ^ (a >> 6), a ^ (a >> 5) for 64-bit
+		step(i+1, off, a^(a>>p.step[1]))
+		// step3: a = a ^ (a << 2), a ^ (a << 12) for 64-bit
+		step(i+2, off, a^(a<<p.step[2]))
+		// step4: a = a ^ (a >> 16), a ^ (a >> 33) for 64-bit
+		step(i+3, off, a^(a>>p.step[3]))
+	}
+
+	return a, b, c
+}
+
+// refillCT is refill with the secret-indexed state reads done by indCT. It
+// is kept apart so the default kernel pays nothing for the option.
+func refillCT[T Word](m, r []T, a, b, c T) (T, T, T) {
+	p := paramsOf[T]()
+	n := len(m)
+	log := uint(bits.TrailingZeros(uint(n)))
+
+	b += c + 1
+	c++
+	for i := range m {
+		var mix T
+		switch i % 4 {
+		case 0:
+			mix = a ^ (a << p.step[0])
+			if p.bits == 64 {
+				mix = ^mix
+			}
+		case 1:
+			mix = a ^ (a >> p.step[1])
+		case 2:
+			mix = a ^ (a << p.step[2])
+		case 3:
+			mix = a ^ (a >> p.step[3])
+		}
+		a = mix + m[(i+n/2)&(n-1)]
+		x := m[i]
+		y := indCT(m, x, p.shift) + a + b
+		m[i] = y
+		b = indCT(m, y>>log, p.shift) + x
+		r[i] = b
 	}
+	return a, b, c
+}
+
+// Generic implementation of ind function: it picks the state word at the
+// byte offset x & ((len(m)-1) << shift), as the C macro does.
+func ind[T Word](m []T, x T, shift uint) T {
+	return m[(x&(T(len(m)-1)<<shift))>>shift]
+}
+
+// indCT returns the same word as ind, reading every word of m and keeping
+// the wanted one with a mask, so the memory access pattern does not depend
+// on x.
+func indCT[T Word](m []T, x T, shift uint) T {
+	i := int32((x & (T(len(m)-1) << shift)) >> shift)
+	var v T
+	for j, w := range m {
+		v |= w & -T(subtle.ConstantTimeEq(int32(j), i))
+	}
+	return v
 }
 
 // Generic implementation of mix function
-func mix[T uint32 | uint64](a, b, c, d, e, f, g, h T) (T, T, T, T, T, T, T, T) {
-	switch any(a).(type) {
-	case uint32:
-		a ^= b << 11
-		d += a
-		b += c
-		b ^= just(c) >> 2
-		e += b
-		c += d
-		c ^= d << 8
-		f += c
-		d += e
-		d ^= just(e) >> 16
-		g += d
-		e += f
-		e ^= f << 10
-		h += e
-		f += g
-		f ^= just(g) >> 4
-		a += f
-		g += h
-		g ^= h << 8
-		b += g
-		h += a
-		h ^= just(a) >> 9
-		c += h
-		a += b
-	case uint64:
+func mix[T Word](a, b, c, d, e, f, g, h T) (T, T, T, T, T, T, T, T) {
+	p := paramsOf[T]()
+	if p.bits == 64 {
 		a -= e
-		f ^= just(h) >> 9
+		f ^= h >> p.mix[0]
 		h += a
 		b -= f
-		g ^= a << 9
+		g ^= a << p.mix[1]
 		a += b
 		c -= g
-		h ^= just(b) >> 23
+		h ^= b >> p.mix[2]
 		b += c
 		d -= h
-		a ^= c << 15
+		a ^= c << p.mix[3]
 		c += d
 		e -= a
-		b ^= just(d) >> 14
+		b ^= d >> p.mix[4]
 		d += e
 		f -= b
-		c ^= e << 20
+		c ^= e << p.mix[5]
 		e += f
 		g -= c
-		d ^= just(f) >> 17
+		d ^= f >> p.mix[6]
 		f += g
 		h -= d
-		e ^= g << 14
+		e ^= g << p.mix[7]
 		g += h
+		return a, b, c, d, e, f, g, h
 	}
+
+	a ^= b << p.mix[0]
+	d += a
+	b += c
+	b ^= c >> p.mix[1]
+	e += b
+	c += d
+	c ^= d << p.mix[2]
+	f += c
+	d += e
+	d ^= e >> p.mix[3]
+	g += d
+	e += f
+	e ^= f << p.mix[4]
+	h += e
+	f += g
+	f ^= g >> p.mix[5]
+	a += f
+	g += h
+	g ^= h << p.mix[6]
+	b += g
+	h += a
+	h ^= a >> p.mix[7]
+	c += h
+	a += b
 	return a, b, c, d, e, f, g, h
 }
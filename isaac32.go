@@ -7,14 +7,22 @@ import (
 
 type UINT32_C = uint32
 
+// defaultInit32 holds the initial values of the C version, derived from the
+// golden ratio by InitValues.
+var defaultInit32 = InitValues[uint32]()
+
 // ISAAC32 struct for 32-bit implementation
 type ISAAC32 struct {
-	m  [Words]uint32 // state table
-	r  []uint32      // result table
-	a  uint32
-	b  uint32
-	c  uint32
-	mu sync.Mutex // mutex for concurrency safety
+	m         [Words]uint32 // state table
+	r         [Words]uint32 // result table
+	n         int           // unread results left in r
+	a         uint32
+	b         uint32
+	c         uint32
+	init      [8]uint32 // initial values given to the last Seed, reused by Mix
+	custom    bool      // init holds custom initial values
+	destroyed bool
+	mu        sync.Mutex // mutex for concurrency safety
 }
 
 func just32(a uint32) uint32 {
@@ -117,31 +125,20 @@ func (s *ISAAC32) Seed(seed [Words]uint32, initValues ...uint32) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
 	if len(initValues) > 0 && len(initValues) != 8 {
 		panic("isaac: need exactly 8 initial values")
 	}
 
 	// Use the same initial values as the C version
-	var a, b, c, d, e, f, g, h uint32
+	v := defaultInit32
 	if len(initValues) == 8 {
-		a = initValues[0]
-		b = initValues[1]
-		c = initValues[2]
-		d = initValues[3]
-		e = initValues[4]
-		f = initValues[5]
-		g = initValues[6]
-		h = initValues[7]
-	} else {
-		a = 0x1367df5a
-		b = 0x95d90059
-		c = 0xc3163e4b
-		d = 0x0f421ad8
-		e = 0xd92a4a78
-		f = 0xa51a3c49
-		g = 0xc4efea1b
-		h = 0x30609119
+		copy(v[:], initValues)
 	}
+	s.init, s.custom = v, len(initValues) == 8
+	a, b, c, d, e, f, g, h := v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7]
 
 	// Initialize m array
 	for i := 0; i < Words; i++ {
@@ -172,6 +169,7 @@ func (s *ISAAC32) Seed(seed [Words]uint32, initValues ...uint32) {
 		}
 	}
 
+	s.n = 0
 	s.a = 0
 	s.b = 0
 	s.c = 0
@@ -182,6 +180,9 @@ func (s *ISAAC32) Refill(r *[Words]uint32) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
 	s.isaac_refill(r)
 }
 
@@ -190,12 +191,28 @@ func (s *ISAAC32) Rand() uint32 {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if len(s.r) == 0 {
-		var r [Words]uint32
-		s.Refill(&r)
-		s.r = r[:]
+	if s.destroyed {
+		panic(ErrDestroyed)
 	}
-	result := s.r[0]
-	s.r = s.r[1:]
+	if s.n == 0 {
+		s.isaac_refill(&s.r)
+		s.n = Words
+	}
+	result := s.r[Words-s.n]
+	s.n--
 	return result
 }
+
+// Destroy zeroes the state table, the result table and the registers. Any
+// later call to Seed, Refill or Rand panics with ErrDestroyed.
+func (s *ISAAC32) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wipe(s.m[:])
+	wipe(s.r[:])
+	wipe(s.init[:])
+	s.n = 0
+	s.a, s.b, s.c = 0, 0, 0
+	s.destroyed = true
+}
@@ -285,3 +285,29 @@ func TestIsaac32(t *testing.T) {
 		})
 	}
 }
+
+// TestIsaac32Rand checks that Rand walks through the Refill output. It is
+// also the regression test for a deadlock: Rand used to call Refill, which
+// locks the mutex Rand already holds, so this test hung.
+func TestIsaac32Rand(t *testing.T) {
+	var r [Words]uint32
+	New32().Refill(&r)
+	s := New32()
+	for _, w := range r {
+		require.Equal(t, w, s.Rand())
+	}
+}
+
+// TestIsaac32RandReseed checks that Seed drops results buffered by Rand.
+func TestIsaac32RandReseed(t *testing.T) {
+	var seed [Words]uint32
+	seed[0] = 42
+	s := New32()
+	s.Rand()
+	s.Seed(seed)
+	fresh := New32()
+	fresh.Seed(seed)
+	for range Words + 1 {
+		require.Equal(t, fresh.Rand(), s.Rand())
+	}
+}
@@ -7,14 +7,22 @@ import (
 
 type UINT64_C = uint64
 
+// defaultInit64 holds the initial values of the C version, derived from the
+// golden ratio by InitValues.
+var defaultInit64 = InitValues[uint64]()
+
 // ISAAC64 struct for 64-bit implementation
 type ISAAC64 struct {
-	m  [Words]uint64 // state table
-	r  []uint64      // result table
-	a  uint64
-	b  uint64
-	c  uint64
-	mu sync.Mutex // mutex for concurrency safety
+	m         [Words]uint64 // state table
+	r         [Words]uint64 // result table
+	n         int           // unread results left in r
+	a         uint64
+	b         uint64
+	c         uint64
+	init      [8]uint64 // initial values given to the last Seed, reused by Mix
+	custom    bool      // init holds custom initial values
+	destroyed bool
+	mu        sync.Mutex // mutex for concurrency safety
 }
 
 func just64(a uint64) uint64 {
@@ -60,9 +68,6 @@ func mix64(a, b, c, d, e, f, g, h uint64) (na, nb, nc, nd, ne, nf, ng, nh uint64
 
 // isaac_refill corresponds to the C version of isaac_refill function
 func (s *ISAAC64) isaac_refill(r *[Words]uint64) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	a := s.a
 	b := s.b + (s.c + 1)
 	s.c++
@@ -120,31 +125,20 @@ func (s *ISAAC64) Seed(seed [Words]uint64, initValues ...uint64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
 	if len(initValues) > 0 && len(initValues) != 8 {
 		panic("isaac: need exactly 8 initial values")
 	}
 
 	// Use the same initial values as the C version
-	var a, b, c, d, e, f, g, h uint64
+	v := defaultInit64
 	if len(initValues) == 8 {
-		a = initValues[0]
-		b = initValues[1]
-		c = initValues[2]
-		d = initValues[3]
-		e = initValues[4]
-		f = initValues[5]
-		g = initValues[6]
-		h = initValues[7]
-	} else {
-		a = 0x647c4677a2884b7c
-		b = 0xb9f8b322c73ac862
-		c = 0x8c0ea5053d4712a0
-		d = 0xb29b2e824a595524
-		e = 0x82f053db8355e0ce
-		f = 0x48fe4a0fa5a09315
-		g = 0xae985bf2cbfc89ed
-		h = 0x98f5704f6c44c0ab
+		copy(v[:], initValues)
 	}
+	s.init, s.custom = v, len(initValues) == 8
+	a, b, c, d, e, f, g, h := v[0], v[1], v[2], v[3], v[4], v[5], v[6], v[7]
 
 	// Initialize m array
 	for i := 0; i < Words; i++ {
@@ -175,6 +169,7 @@ func (s *ISAAC64) Seed(seed [Words]uint64, initValues ...uint64) {
 		}
 	}
 
+	s.n = 0
 	s.a = 0
 	s.b = 0
 	s.c = 0
@@ -182,6 +177,12 @@ func (s *ISAAC64) Seed(seed [Words]uint64, initValues ...uint64) {
 
 // Refill replenishes the random number array
 func (s *ISAAC64) Refill(r *[Words]uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
 	s.isaac_refill(r)
 }
 
@@ -190,12 +191,28 @@ func (s *ISAAC64) Rand() uint64 {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	if len(s.r) == 0 {
-		var r [Words]uint64
-		s.Refill(&r)
-		s.r = r[:]
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
+	if s.n == 0 {
+		s.isaac_refill(&s.r)
+		s.n = Words
 	}
-	result := s.r[0]
-	s.r = s.r[1:]
+	result := s.r[Words-s.n]
+	s.n--
 	return result
 }
+
+// Destroy zeroes the state table, the result table and the registers. Any
+// later call to Seed, Refill or Rand panics with ErrDestroyed.
+func (s *ISAAC64) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wipe(s.m[:])
+	wipe(s.r[:])
+	wipe(s.init[:])
+	s.n = 0
+	s.a, s.b, s.c = 0, 0, 0
+	s.destroyed = true
+}
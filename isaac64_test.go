@@ -319,3 +319,29 @@ func TestWxIsaac64(t *testing.T) {
 		require.Equal(t, keys1, keys2)
 	}
 }
+
+// TestIsaac64Rand checks that Rand walks through the Refill output. It is
+// also the regression test for a deadlock: Rand used to call Refill, which
+// locks the mutex Rand already holds, so this test hung.
+func TestIsaac64Rand(t *testing.T) {
+	var r [Words]uint64
+	New64().Refill(&r)
+	s := New64()
+	for _, w := range r {
+		require.Equal(t, w, s.Rand())
+	}
+}
+
+// TestIsaac64RandReseed checks that Seed drops results buffered by Rand.
+func TestIsaac64RandReseed(t *testing.T) {
+	var seed [Words]uint64
+	seed[0] = 42
+	s := New64()
+	s.Rand()
+	s.Seed(seed)
+	fresh := New64()
+	fresh.Seed(seed)
+	for range Words + 1 {
+		require.Equal(t, fresh.Rand(), s.Rand())
+	}
+}
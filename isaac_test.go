@@ -566,3 +566,113 @@ func TestISAAC32(t *testing.T) {
 		})
 	}
 }
+
+// TestInitValues checks the derived initial values against the constants
+// hard-coded in coreutils' rand-isaac.c.
+func TestInitValues(t *testing.T) {
+	require.Equal(t, [8]uint32{
+		0x1367df5a, 0x95d90059, 0xc3163e4b, 0x0f421ad8,
+		0xd92a4a78, 0xa51a3c49, 0xc4efea1b, 0x30609119,
+	}, InitValues[uint32]())
+	require.Equal(t, [8]uint64{
+		0x647c4677a2884b7c, 0xb9f8b322c73ac862, 0x8c0ea5053d4712a0, 0xb29b2e824a595524,
+		0x82f053db8355e0ce, 0x48fe4a0fa5a09315, 0xae985bf2cbfc89ed, 0x98f5704f6c44c0ab,
+	}, InitValues[uint64]())
+
+	require.Equal(t, InitValues[uint32](), InitValues(WithBase(GoldenRatio[uint32]())))
+	require.Equal(t, uint16(0x9e37), GoldenRatio[uint16]())
+
+	var base [8]uint64
+	require.Equal(t, base, InitValues(WithBase(base[:]...), WithRounds[uint64](0)))
+	require.NotEqual(t, InitValues[uint64](), InitValues(WithBase[uint64](1)))
+	require.Panics(t, func() { WithBase[uint32](1, 2) })
+}
+
+// TestSeedInitValues checks that passing derived values to Seed matches
+// the default seeding.
+func TestSeedInitValues(t *testing.T) {
+	s1 := New32()
+	s2 := New32()
+	v := InitValues[uint32]()
+	s2.Seed([Words]uint32{}, v[:]...)
+	var r1, r2 [Words]uint32
+	s1.Refill(&r1)
+	s2.Refill(&r2)
+	require.Equal(t, r1, r2)
+}
+
+// testConstantTime checks that constant-time lookups give the same output.
+func testConstantTime[T Word](t *testing.T) {
+	var seed [Words]T
+	for i := range seed {
+		seed[i] = T(uint64(i) * 0x9e3779b9)
+	}
+	s1, s2 := New[T](), New[T]()
+	s1.Seed(seed)
+	s2.Seed(seed)
+	s2.SetConstantTime(true)
+	var r1, r2 [Words]T
+	for range 4 {
+		s1.Refill(&r1)
+		s2.Refill(&r2)
+		require.Equal(t, r1, r2)
+		require.Equal(t, s1.Rand(), s2.Rand())
+	}
+}
+
+func TestConstantTime(t *testing.T) {
+	testConstantTime[uint8](t)
+	testConstantTime[uint16](t)
+	testConstantTime[uint32](t)
+	testConstantTime[uint64](t)
+}
+
+// benchmarkRefill measures one Refill of an instance prepared by setup.
+func benchmarkRefill[T Word](b *testing.B, setup func(*ISAAC[T])) {
+	s := New[T]()
+	setup(s)
+	var r [Words]T
+	b.SetBytes(int64(Words * paramsOf[T]().bits / 8))
+	for range b.N {
+		s.Refill(&r)
+	}
+}
+
+func BenchmarkRefill(b *testing.B) {
+	b.Run("32", func(b *testing.B) { benchmarkRefill(b, func(*ISAAC[uint32]) {}) })
+	b.Run("32/ConstantTime", func(b *testing.B) { benchmarkRefill(b, func(s *ISAAC[uint32]) { s.SetConstantTime(true) }) })
+	b.Run("64", func(b *testing.B) { benchmarkRefill(b, func(*ISAAC[uint64]) {}) })
+	b.Run("64/ConstantTime", func(b *testing.B) { benchmarkRefill(b, func(s *ISAAC[uint64]) { s.SetConstantTime(true) }) })
+	b.Run("64/KeyErasure", func(b *testing.B) { benchmarkRefill(b, func(s *ISAAC[uint64]) { s.SetKeyErasure(true) }) })
+}
+
+func TestKeyErasure(t *testing.T) {
+	seed := [Words]uint64{1, 2, 3}
+	plain, erased := New[uint64](), New[uint64]()
+	plain.Seed(seed)
+	erased.Seed(seed)
+	erased.SetKeyErasure(true)
+
+	// The first block is unchanged; then the state is replaced by the mixed
+	// hidden block that follows it.
+	var r1, r2, hidden [Words]uint64
+	plain.Refill(&r1)
+	erased.Refill(&r2)
+	require.Equal(t, r1, r2)
+	plain.Refill(&hidden)
+	seedState(hidden[:], InitValues[uint64]())
+	require.Equal(t, hidden, erased.m)
+	require.Equal(t, plain.a, erased.a)
+	require.Equal(t, plain.b, erased.b)
+	require.Equal(t, plain.c, erased.c)
+
+	plain.Refill(&r1)
+	erased.Refill(&r2)
+	require.NotEqual(t, r1, r2)
+
+	// Rand zeroes results once returned.
+	w := erased.Rand()
+	require.NotZero(t, w)
+	require.Zero(t, erased.r[0])
+	require.NotZero(t, erased.r[1])
+}
@@ -0,0 +1,115 @@
+package isaac
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// ErrLockUnsupported is returned by NewLocked on platforms without locked
+// memory support.
+var ErrLockUnsupported = errors.New("isaac: locked memory is not supported on this platform")
+
+// Locked is an ISAAC instance whose state table and Rand result buffer live
+// in memory that is locked against swapping and excluded from core dumps
+// (mlock and MADV_DONTDUMP on Linux). The memory is outside the Go heap and
+// must be released with Close; a finalizer releases it if Close is never
+// called. Seeds passed to Seed and results written by Refill pass through
+// caller memory and are the caller's to protect.
+//
+// It is safe for concurrent use, but no method may be called after Close.
+type Locked[T Word] struct {
+	mu  sync.RWMutex // write-held by Close
+	mem []byte       // mapping holding s, nil after Close
+	s   *ISAAC[T]
+}
+
+// NewLocked creates a locked instance seeded with zeros, like New.
+func NewLocked[T Word]() (*Locked[T], error) {
+	mem, err := lockedAlloc(int(unsafe.Sizeof(ISAAC[T]{})))
+	if err != nil {
+		return nil, err
+	}
+	// ISAAC[T] holds no pointers, so it may live outside the Go heap.
+	l := &Locked[T]{mem: mem, s: (*ISAAC[T])(unsafe.Pointer(&mem[0]))}
+	l.s.Seed([Words]T{})
+	runtime.SetFinalizer(l, func(l *Locked[T]) { _ = l.Close() })
+	return l, nil
+}
+
+// state returns the locked instance, panicking with ErrDestroyed after
+// Close. The caller must hold l.mu for reading.
+func (l *Locked[T]) state() *ISAAC[T] {
+	if l.s == nil {
+		panic(ErrDestroyed)
+	}
+	return l.s
+}
+
+// Seed initializes the instance, see ISAAC.Seed.
+func (l *Locked[T]) Seed(seed [Words]T, initValues ...T) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.state().Seed(seed, initValues...)
+}
+
+// Refill replenishes the random number array, see ISAAC.Refill.
+func (l *Locked[T]) Refill(r *[Words]T) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.state().Refill(r)
+}
+
+// Rand returns the next random number, see ISAAC.Rand.
+func (l *Locked[T]) Rand() T {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.state().Rand()
+}
+
+// SetConstantTime selects constant-time state lookups, see
+// ISAAC.SetConstantTime.
+func (l *Locked[T]) SetConstantTime(on bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.state().SetConstantTime(on)
+}
+
+// SetKeyErasure turns fast key erasure on or off, see ISAAC.SetKeyErasure.
+func (l *Locked[T]) SetKeyErasure(on bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.state().SetKeyErasure(on)
+}
+
+// Mix folds words into the state, see ISAAC.Mix.
+func (l *Locked[T]) Mix(words []T) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.state().Mix(words)
+}
+
+// AddEntropy mixes p into the state, see ISAAC.AddEntropy.
+func (l *Locked[T]) AddEntropy(p []byte) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	l.state().AddEntropy(p)
+}
+
+// Close zeroes the state and unlocks and unmaps its memory. Later calls to
+// Seed, Refill and Rand panic with ErrDestroyed; further Close calls return
+// nil.
+func (l *Locked[T]) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.s == nil {
+		return nil
+	}
+	l.s.Destroy()
+	err := lockedFree(l.mem)
+	l.mem, l.s = nil, nil
+	runtime.SetFinalizer(l, nil)
+	return err
+}
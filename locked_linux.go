@@ -0,0 +1,36 @@
+package isaac
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+)
+
+// madvDontDump is MADV_DONTDUMP from <sys/mman.h>, missing from package
+// syscall.
+const madvDontDump = 0x10
+
+// lockedAlloc maps size bytes of anonymous memory, locks them in RAM and
+// excludes them from core dumps.
+func lockedAlloc(size int) ([]byte, error) {
+	mem, err := syscall.Mmap(-1, 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_PRIVATE|syscall.MAP_ANON)
+	if err != nil {
+		return nil, fmt.Errorf("isaac: mmap: %w", err)
+	}
+	if err := syscall.Mlock(mem); err != nil {
+		_ = syscall.Munmap(mem)
+		return nil, fmt.Errorf("isaac: mlock (check RLIMIT_MEMLOCK): %w", err)
+	}
+	if err := syscall.Madvise(mem, madvDontDump); err != nil {
+		_ = syscall.Munlock(mem)
+		_ = syscall.Munmap(mem)
+		return nil, fmt.Errorf("isaac: madvise: %w", err)
+	}
+	return mem, nil
+}
+
+// lockedFree unlocks and unmaps memory from lockedAlloc. The caller zeroes
+// it first.
+func lockedFree(mem []byte) error {
+	return errors.Join(syscall.Munlock(mem), syscall.Munmap(mem))
+}
@@ -0,0 +1,11 @@
+//go:build !linux
+
+package isaac
+
+func lockedAlloc(int) ([]byte, error) {
+	return nil, ErrLockUnsupported
+}
+
+func lockedFree([]byte) error {
+	return nil
+}
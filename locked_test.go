@@ -0,0 +1,50 @@
+package isaac
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocked(t *testing.T) {
+	l, err := NewLocked[uint64]()
+	if runtime.GOOS != "linux" {
+		require.ErrorIs(t, err, ErrLockUnsupported)
+		return
+	}
+	require.NoError(t, err)
+
+	seed := [Words]uint64{1, 2, 3}
+	want := New[uint64]()
+	want.Seed(seed)
+	l.Seed(seed)
+	for range Words + 10 {
+		require.Equal(t, want.Rand(), l.Rand())
+	}
+	var r, wr [Words]uint64
+	l.Refill(&r)
+	want.Refill(&wr)
+	require.Equal(t, wr, r)
+
+	require.NoError(t, l.Close())
+	require.NoError(t, l.Close())
+	require.PanicsWithValue(t, ErrDestroyed, func() { l.Rand() })
+	require.PanicsWithValue(t, ErrDestroyed, func() { l.Refill(&r) })
+	require.PanicsWithValue(t, ErrDestroyed, func() { l.Seed(seed) })
+}
+
+func TestLocked32(t *testing.T) {
+	l, err := NewLocked[uint32]()
+	if runtime.GOOS != "linux" {
+		require.ErrorIs(t, err, ErrLockUnsupported)
+		return
+	}
+	require.NoError(t, err)
+	defer l.Close()
+
+	want := New[uint32]()
+	for range 3 {
+		require.Equal(t, want.Rand(), l.Rand())
+	}
+}
@@ -0,0 +1,302 @@
+package isaac
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Errors returned by NewGenerator for invalid configurations.
+var (
+	ErrWidth       = errors.New("isaac: width must be 32 or 64")
+	ErrSeedLength  = errors.New("isaac: seed longer than the state table")
+	ErrInitValues  = errors.New("isaac: need exactly 8 initial values")
+	ErrProfile     = errors.New("isaac: unknown profile")
+	ErrSeedSources = errors.New("isaac: WithSeed and WithEntropy are mutually exclusive")
+	ErrNonce       = errors.New("isaac: WithNonce needs a byte seed")
+	ErrNonceLength = errors.New("isaac: invalid nonce length")
+)
+
+// Profile selects how results of the ISAAC kernel are turned into an output
+// stream, matching the layout of a well-known implementation.
+type Profile int
+
+const (
+	// Coreutils consumes each result block from the first word to the last
+	// and writes words little-endian, like GNU Coreutils' randread on x86.
+	Coreutils Profile = iota
+	// Jenkins consumes each result block from the last word to the first,
+	// like the rand macro of Jenkins' reference code.
+	Jenkins
+	// Rust matches the rand_isaac crate: Jenkins' word order, little-endian
+	// bytes, and 64-bit values from two 32-bit words built low word first.
+	Rust
+	// Java matches Apache Commons Math's ISAACRandom: Jenkins' word order,
+	// little-endian bytes, and 64-bit values from two 32-bit words built
+	// high word first.
+	Java
+	// WeChat matches the wxisaac64 keystream of WeChat media: Jenkins' word
+	// order with every word written big-endian, so each result block is
+	// the byte-reversed little-endian encoding. It is meant for width 64.
+	WeChat
+)
+
+var profileNames = [...]string{"coreutils", "jenkins", "rust", "java", "wechat"}
+
+// Profiles returns all known profiles.
+func Profiles() []Profile {
+	ps := make([]Profile, len(profileNames))
+	for i := range ps {
+		ps[i] = Profile(i)
+	}
+	return ps
+}
+
+// String returns the lower-case profile name.
+func (p Profile) String() string {
+	if p < 0 || int(p) >= len(profileNames) {
+		return fmt.Sprintf("Profile(%d)", int(p))
+	}
+	return profileNames[p]
+}
+
+// ParseProfile returns the profile with the given String name.
+func ParseProfile(name string) (Profile, error) {
+	for i, n := range profileNames {
+		if n == name {
+			return Profile(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w %q", ErrProfile, name)
+}
+
+// layout describes how a profile lays out output.
+type layout struct {
+	reverse bool             // consume result blocks from the last word
+	hiFirst bool             // build 64-bit values from 32-bit words high word first
+	order   binary.ByteOrder // byte order of Read
+}
+
+func (p Profile) layout() (layout, error) {
+	switch p {
+	case Coreutils:
+		return layout{order: binary.LittleEndian}, nil
+	case Jenkins, Rust:
+		return layout{reverse: true, order: binary.LittleEndian}, nil
+	case Java:
+		return layout{reverse: true, hiFirst: true, order: binary.LittleEndian}, nil
+	case WeChat:
+		return layout{reverse: true, order: binary.BigEndian}, nil
+	}
+	return layout{}, fmt.Errorf("%w %d", ErrProfile, int(p))
+}
+
+// config collects the settings of NewGenerator.
+type config struct {
+	width      int // 0 until set by WithWidth
+	wordWidth  int // width implied by typed seed or init values, 0 if none
+	seed       []byte
+	seedWords  []uint64
+	initValues []uint64
+	profile    Profile
+	order      binary.ByteOrder
+	entropy    io.Reader
+	nonce      []byte
+	checkpoint int64 // blocks between checkpoints of NewDecryptReaderAt
+	ct         bool
+	erase      bool
+	health     *HealthTest
+	selfTest   bool
+	strict     bool
+}
+
+// Option configures a Generator created by NewGenerator.
+type Option func(*config) error
+
+// WithWidth selects the 32-bit or 64-bit ISAAC kernel. The default is 64,
+// or the width of the words given to WithSeed or WithInitValues.
+func WithWidth(bits int) Option {
+	return func(c *config) error {
+		if bits != 32 && bits != 64 {
+			return fmt.Errorf("%w, got %d", ErrWidth, bits)
+		}
+		c.width = bits
+		return nil
+	}
+}
+
+// WithSeed sets the seed. Bytes are packed into words with the configured
+// byte order; words are used as they are. Seeds shorter than the state
+// table are padded with zeros. Without a seed the generator is seeded with
+// zeros, like New.
+func WithSeed[S []byte | []uint32 | []uint64](seed S) Option {
+	return func(c *config) error {
+		switch s := any(seed).(type) {
+		case []byte:
+			c.seed, c.seedWords = append([]byte{}, s...), nil
+		case []uint32:
+			if err := c.setWordWidth(32); err != nil {
+				return err
+			}
+			c.seed, c.seedWords = nil, widen(s)
+		case []uint64:
+			if err := c.setWordWidth(64); err != nil {
+				return err
+			}
+			c.seed, c.seedWords = nil, widen(s)
+		}
+		return nil
+	}
+}
+
+// WithInitValues replaces the initial values Seed starts mixing from, see
+// InitValues. Exactly 8 values must be given.
+func WithInitValues[W uint32 | uint64](values ...W) Option {
+	return func(c *config) error {
+		if len(values) != 8 {
+			return fmt.Errorf("%w, got %d", ErrInitValues, len(values))
+		}
+		if err := c.setWordWidth(int(paramsOf[W]().bits)); err != nil {
+			return err
+		}
+		c.initValues = widen(values)
+		return nil
+	}
+}
+
+// WithProfile selects the output layout. The default is Coreutils.
+func WithProfile(p Profile) Option {
+	return func(c *config) error {
+		if _, err := p.layout(); err != nil {
+			return err
+		}
+		c.profile = p
+		return nil
+	}
+}
+
+// WithByteOrder overrides the byte order of the profile, used by Read and
+// to pack byte seeds into words.
+func WithByteOrder(order binary.ByteOrder) Option {
+	return func(c *config) error {
+		c.order = order
+		return nil
+	}
+}
+
+// WithEntropy seeds the generator with a full state table read from r,
+// typically crypto/rand.Reader.
+func WithEntropy(r io.Reader) Option {
+	return func(c *config) error {
+		c.entropy = r
+		return nil
+	}
+}
+
+// WithNonce combines a byte seed, used as a long-term key, with a
+// per-message nonce, so one key yields a distinct stream per nonce. The seed
+// table of S bytes (1024 for width 32, 2048 for width 64) is then laid out
+// in words of the configured byte order as:
+//
+//	bytes [0, S/2)        key, zero padded
+//	bytes [S/2, S-2w)     nonce, zero padded
+//	word  Words-2         key length in bytes
+//	word  Words-1         nonce length in bytes
+//
+// where w is the word size in bytes. The lengths keep keys and nonces that
+// differ only in trailing zeros apart. The key may be at most S/2 bytes and
+// the nonce between 1 and S/2-2w bytes.
+func WithNonce(nonce []byte) Option {
+	return func(c *config) error {
+		if len(nonce) == 0 {
+			return fmt.Errorf("%w: empty nonce", ErrNonceLength)
+		}
+		c.nonce = append([]byte{}, nonce...)
+		return nil
+	}
+}
+
+// WithCheckpointInterval sets how many result blocks apart
+// NewDecryptReaderAt caches generator states. Other constructors ignore it.
+func WithCheckpointInterval(blocks int) Option {
+	return func(c *config) error {
+		if blocks < 1 {
+			return fmt.Errorf("isaac: invalid checkpoint interval %d", blocks)
+		}
+		c.checkpoint = int64(blocks)
+		return nil
+	}
+}
+
+// WithConstantTime makes the generator read its state table in constant
+// time, see ISAAC.SetConstantTime. The output is unchanged.
+func WithConstantTime() Option {
+	return func(c *config) error {
+		c.ct = true
+		return nil
+	}
+}
+
+// WithKeyErasure turns on fast key erasure after every result block, see
+// ISAAC.SetKeyErasure. It changes the output, which is then no longer
+// compatible with any profile's reference implementation.
+func WithKeyErasure() Option {
+	return func(c *config) error {
+		c.erase = true
+		return nil
+	}
+}
+
+// WithHealthTest runs h over the entropy read by WithEntropy and checks
+// every result block for stuck output as StuckBlock does, keeping only a
+// digest of the previous block. Failures are reported to h.OnFailure;
+// NewGenerator returns entropy failures, and after stuck output Read
+// returns the error while Uint32 and Uint64 panic with it.
+func WithHealthTest(h *HealthTest) Option {
+	return func(c *config) error {
+		c.health = h
+		return nil
+	}
+}
+
+// WithSelfTest runs SelfTest the first time it is used in the process and
+// fails with its error, then and on every later use, if a kernel is broken.
+func WithSelfTest() Option {
+	return func(c *config) error {
+		c.selfTest = true
+		return nil
+	}
+}
+
+// WithStrictSeed makes NewGenerator fail with an error wrapping ErrWeakSeed
+// when the seed is weak, including the all-zero table used when no seed is
+// given. The checks of CheckSeed run on the seed material as supplied,
+// before it is padded with zeros: the words or bytes given to WithSeed, the
+// key given with WithNonce, or the table read by WithEntropy. Material must
+// be at least 16 bytes long.
+func WithStrictSeed() Option {
+	return func(c *config) error {
+		c.strict = true
+		return nil
+	}
+}
+
+// setWordWidth records the width implied by typed words, rejecting words of
+// a different width given by another option.
+func (c *config) setWordWidth(bits int) error {
+	if c.wordWidth != 0 && c.wordWidth != bits {
+		return fmt.Errorf("%w: %d-bit and %d-bit words mixed", ErrWidth, c.wordWidth, bits)
+	}
+	c.wordWidth = bits
+	return nil
+}
+
+// widen copies words into a []uint64.
+func widen[W uint32 | uint64](words []W) []uint64 {
+	out := make([]uint64, len(words))
+	for i, w := range words {
+		out[i] = uint64(w)
+	}
+	return out
+}
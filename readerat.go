@@ -0,0 +1,135 @@
+package isaac
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// defaultCheckpointInterval is the number of result blocks between cached
+// generator states: 2 MiB of keystream for width 64, 1 MiB for width 32.
+const defaultCheckpointInterval = 1024
+
+// DecryptReaderAt decrypts an ISAAC-encrypted source at arbitrary offsets.
+// The keystream at an offset is computed by copying the nearest cached
+// generator state before it and fast-forwarding whole result blocks, so
+// range requests do not regenerate the keystream from the start. States
+// are cached every checkpoint interval as the reader moves through the
+// file. It is safe for concurrent use; wrap it in an io.SectionReader to
+// get an io.ReadSeeker, for example for http.ServeContent.
+type DecryptReaderAt struct {
+	src   io.ReaderAt
+	every int64 // blocks between checkpoints
+	block int64 // keystream bytes per result block
+
+	mu          sync.Mutex
+	checkpoints []keystream // state at block i*every, never advanced
+	destroyed   bool
+}
+
+var _ io.ReaderAt = (*DecryptReaderAt)(nil)
+
+// NewDecryptReaderAt returns a reader decrypting src, which was encrypted
+// from offset 0 with the keystream of NewCipher(key, opts...). Add
+// WithNonce to match NewCipherWithNonce, and WithCheckpointInterval to
+// trade memory for seek speed.
+func NewDecryptReaderAt(src io.ReaderAt, key []byte, opts ...Option) (*DecryptReaderAt, error) {
+	if len(key) == 0 {
+		return nil, ErrKeyLength
+	}
+	g, c, err := build(append(opts, WithSeed(key)))
+	if errors.Is(err, ErrSeedLength) {
+		return nil, ErrKeyLength
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	d := &DecryptReaderAt{
+		src:         src,
+		every:       c.checkpoint,
+		block:       int64(g.blockBytes()),
+		checkpoints: []keystream{g},
+	}
+	if d.every == 0 {
+		d.every = defaultCheckpointInterval
+	}
+	return d, nil
+}
+
+// ReadAt implements io.ReaderAt: it reads ciphertext from the source at off
+// and returns the plaintext.
+func (d *DecryptReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, fmt.Errorf("isaac: negative offset %d", off)
+	}
+	n, err := d.src.ReadAt(p, off)
+	if n > 0 {
+		if xerr := d.xorAt(p[:n], off); xerr != nil {
+			return 0, xerr
+		}
+	}
+	return n, err
+}
+
+// xorAt XORs p with the keystream starting at byte offset off.
+func (d *DecryptReaderAt) xorAt(p []byte, off int64) error {
+	block := off / d.block
+	ks, err := d.at(block)
+	if err != nil {
+		return err
+	}
+	defer ks.Destroy()
+
+	// Drop the keystream bytes before off within its block.
+	skip := make([]byte, off-block*d.block)
+	_, _ = ks.Read(skip)
+
+	var buf [1024]byte
+	for len(p) > 0 {
+		n := min(len(p), len(buf))
+		_, _ = ks.Read(buf[:n])
+		for i := range n {
+			p[i] ^= buf[i]
+		}
+		p = p[n:]
+	}
+	wipe(skip)
+	wipe(buf[:])
+	return nil
+}
+
+// at returns a private keystream positioned at the start of block.
+func (d *DecryptReaderAt) at(block int64) (keystream, error) {
+	k := block / d.every
+
+	d.mu.Lock()
+	if d.destroyed {
+		d.mu.Unlock()
+		return nil, ErrDestroyed
+	}
+	for int64(len(d.checkpoints)) <= k {
+		next := d.checkpoints[len(d.checkpoints)-1].clone()
+		next.skip(d.every)
+		d.checkpoints = append(d.checkpoints, next)
+	}
+	ks := d.checkpoints[k].clone()
+	d.mu.Unlock()
+
+	ks.skip(block - k*d.every)
+	return ks, nil
+}
+
+// Destroy zeroes all cached generator states. Later calls to ReadAt return
+// ErrDestroyed.
+func (d *DecryptReaderAt) Destroy() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, ks := range d.checkpoints {
+		ks.Destroy()
+	}
+	d.checkpoints = nil
+	d.destroyed = true
+}
@@ -0,0 +1,96 @@
+package isaac
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// encrypted returns size bytes of plaintext and its encryption under key.
+func encrypted(t *testing.T, size int, key []byte, opts ...Option) ([]byte, []byte) {
+	t.Helper()
+	plain := make([]byte, size)
+	for i := range plain {
+		plain[i] = byte(i * 7)
+	}
+	c, err := NewCipher(key, opts...)
+	require.NoError(t, err)
+	ct := make([]byte, size)
+	c.XORKeyStream(ct, plain)
+	return plain, ct
+}
+
+func TestDecryptReaderAt(t *testing.T) {
+	key := []byte("media key")
+	for _, opts := range [][]Option{
+		{WithCheckpointInterval(3)},
+		{WithWidth(32), WithProfile(Jenkins), WithCheckpointInterval(2)},
+		{WithNonce([]byte("nonce")), WithCheckpointInterval(5)},
+		nil,
+	} {
+		plain, ct := encrypted(t, 100_000, key, opts...)
+		d, err := NewDecryptReaderAt(bytes.NewReader(ct), key, opts...)
+		require.NoError(t, err)
+
+		rng := rand.New(rand.NewPCG(1, 2))
+		for range 200 {
+			off := rng.IntN(len(ct))
+			n := rng.IntN(5000)
+			p := make([]byte, n)
+			got, err := d.ReadAt(p, int64(off))
+			want := min(n, len(ct)-off)
+			require.Equal(t, want, got)
+			if got < n {
+				require.ErrorIs(t, err, io.EOF)
+			}
+			require.Equal(t, plain[off:off+got], p[:got])
+		}
+
+		all, err := io.ReadAll(io.NewSectionReader(d, 0, int64(len(ct))))
+		require.NoError(t, err)
+		require.Equal(t, plain, all)
+	}
+}
+
+func TestDecryptReaderAtConcurrent(t *testing.T) {
+	key := []byte("media key")
+	plain, ct := encrypted(t, 1<<18, key)
+	d, err := NewDecryptReaderAt(bytes.NewReader(ct), key, WithCheckpointInterval(4))
+	require.NoError(t, err)
+
+	// Assert on the test goroutine: FailNow must not run in the workers.
+	got := make([][]byte, 8)
+	errs := make([]error, 8)
+	var wg sync.WaitGroup
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			off := len(ct) - (i+1)*20_000
+			got[i] = make([]byte, 10_000)
+			_, errs[i] = d.ReadAt(got[i], int64(off))
+		}()
+	}
+	wg.Wait()
+	for i, p := range got {
+		off := len(ct) - (i+1)*20_000
+		require.NoError(t, errs[i])
+		require.Equal(t, plain[off:off+len(p)], p)
+	}
+}
+
+func TestDecryptReaderAtErrors(t *testing.T) {
+	_, err := NewDecryptReaderAt(bytes.NewReader(nil), nil)
+	require.ErrorIs(t, err, ErrKeyLength)
+	_, err = NewDecryptReaderAt(bytes.NewReader(nil), []byte("k"), WithCheckpointInterval(0))
+	require.Error(t, err)
+
+	d, err := NewDecryptReaderAt(bytes.NewReader(nil), []byte("k"))
+	require.NoError(t, err)
+	_, err = d.ReadAt(make([]byte, 1), -1)
+	require.Error(t, err)
+}
@@ -0,0 +1,251 @@
+package isaac
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Defaults of NewReseeding.
+const (
+	DefaultReseedWords    = 1 << 20
+	DefaultReseedInterval = 10 * time.Minute
+	reseedBytes           = 64 // entropy mixed in per reseed
+)
+
+// ReseedStats counts the activity of a Reseeding generator.
+type ReseedStats struct {
+	Words       uint64    // words output since creation
+	Reseeds     uint64    // successful reseeds, including ForkReseeds
+	ForkReseeds uint64    // reseeds caused by a process ID change
+	Failures    uint64    // reseeds that failed to read entropy
+	LastReseed  time.Time // time of the last successful reseed
+	LastError   error     // error of the last failed reseed, nil after a success
+}
+
+// ReseedOption configures NewReseeding.
+type ReseedOption func(*Reseeding) error
+
+// WithReseedEntropy sets the entropy source. The default is crypto/rand.
+func WithReseedEntropy(r io.Reader) ReseedOption {
+	return func(s *Reseeding) error {
+		s.entropy = r
+		return nil
+	}
+}
+
+// WithReseedWords reseeds after n output words, 0 to disable. The default
+// is DefaultReseedWords.
+func WithReseedWords(n uint64) ReseedOption {
+	return func(s *Reseeding) error {
+		s.every = n
+		return nil
+	}
+}
+
+// WithReseedInterval reseeds when d has passed since the last reseed, 0 to
+// disable. The default is DefaultReseedInterval.
+func WithReseedInterval(d time.Duration) ReseedOption {
+	return func(s *Reseeding) error {
+		if d < 0 {
+			return fmt.Errorf("isaac: negative reseed interval %v", d)
+		}
+		s.interval = d
+		return nil
+	}
+}
+
+// WithForkDetection turns the process ID check on or off. It is on by
+// default and costs a getpid system call per output call.
+func WithForkDetection(on bool) ReseedOption {
+	return func(s *Reseeding) error {
+		s.forkCheck = on
+		return nil
+	}
+}
+
+// Reseeding wraps a Generator and mixes fresh entropy into it with
+// AddEntropy once a number of words has been output, once a time interval
+// has passed, and when the process ID changes, so a forked child does not
+// repeat its parent's stream. The policy is checked before each output
+// call, so a single large Read may run past the word limit. If reading
+// entropy fails, Read returns the error while Uint32 and Uint64 keep going
+// on the current state and retry on the next call; the failure is counted
+// in Stats. After a fork there is no safe state to go on with, as it would
+// repeat the parent's stream, so Uint32 and Uint64 panic with the error
+// instead. It is safe for concurrent use.
+type Reseeding struct {
+	mu        sync.Mutex
+	g         Generator
+	entropy   io.Reader
+	every     uint64
+	interval  time.Duration
+	forkCheck bool
+	now       func() time.Time
+
+	pid   int
+	last  time.Time // time of the last reseed, or of creation
+	since uint64    // output bytes since the last reseed
+	bytes uint64    // output bytes since creation
+	stats ReseedStats
+	buf   [reseedBytes]byte
+}
+
+var _ Generator = (*Reseeding)(nil)
+
+// NewReseeding wraps g with the reseeding policy set by opts.
+func NewReseeding(g Generator, opts ...ReseedOption) (*Reseeding, error) {
+	s := &Reseeding{
+		g:         g,
+		entropy:   rand.Reader,
+		every:     DefaultReseedWords,
+		interval:  DefaultReseedInterval,
+		forkCheck: true,
+		now:       time.Now,
+		pid:       os.Getpid(),
+	}
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+	s.last = s.now()
+	return s, nil
+}
+
+// due reports whether a reseed is needed before more output, and whether
+// it is because of a process ID change.
+func (s *Reseeding) due() (due, fork bool) {
+	if s.forkCheck && os.Getpid() != s.pid {
+		return true, true
+	}
+	if s.every > 0 && s.since/uint64(s.g.Width()/8) >= s.every {
+		return true, false
+	}
+	return s.interval > 0 && s.now().Sub(s.last) >= s.interval, false
+}
+
+// reseed mixes fresh entropy into the generator. The caller holds mu.
+func (s *Reseeding) reseed(fork bool) error {
+	defer wipe(s.buf[:])
+	if _, err := io.ReadFull(s.entropy, s.buf[:]); err != nil {
+		s.stats.Failures++
+		s.stats.LastError = fmt.Errorf("isaac: reseed: %w", err)
+		return s.stats.LastError
+	}
+	s.g.AddEntropy(s.buf[:])
+	s.pid = os.Getpid()
+	s.last = s.now()
+	s.since = 0
+	s.stats.Reseeds++
+	if fork {
+		s.stats.ForkReseeds++
+	}
+	s.stats.LastReseed = s.last
+	s.stats.LastError = nil
+	return nil
+}
+
+// before runs the policy ahead of output. The caller holds mu.
+func (s *Reseeding) before() error {
+	if due, fork := s.due(); due {
+		return s.reseed(fork)
+	}
+	return nil
+}
+
+// beforeWord runs the policy ahead of Uint32 and Uint64, panicking if a
+// reseed after a fork fails. The caller holds mu.
+func (s *Reseeding) beforeWord() {
+	due, fork := s.due()
+	if !due {
+		return
+	}
+	if err := s.reseed(fork); err != nil && fork {
+		panic(err)
+	}
+}
+
+// after accounts for n output bytes. The caller holds mu.
+func (s *Reseeding) after(n int) {
+	s.since += uint64(n)
+	s.bytes += uint64(n)
+}
+
+// Reseed mixes fresh entropy into the generator now.
+func (s *Reseeding) Reseed() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.reseed(false)
+}
+
+// Stats returns the counters.
+func (s *Reseeding) Stats() ReseedStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st := s.stats
+	st.Words = s.bytes / uint64(s.g.Width()/8)
+	return st
+}
+
+// Read implements Generator. Unlike the generators of NewGenerator it
+// returns an error when a due reseed fails.
+func (s *Reseeding) Read(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.before(); err != nil {
+		return 0, err
+	}
+	n, err := s.g.Read(p)
+	s.after(n)
+	return n, err
+}
+
+// Uint32 implements Generator.
+func (s *Reseeding) Uint32() uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	size := s.g.Width() / 8
+	s.beforeWord()
+	s.after(size)
+	return s.g.Uint32()
+}
+
+// Uint64 implements Generator.
+func (s *Reseeding) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.beforeWord()
+	s.after(8)
+	return s.g.Uint64()
+}
+
+// Width implements Generator.
+func (s *Reseeding) Width() int {
+	return s.g.Width()
+}
+
+// AddEntropy implements Generator.
+func (s *Reseeding) AddEntropy(p []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.g.AddEntropy(p)
+}
+
+// Destroy implements Generator: it destroys the wrapped generator.
+func (s *Reseeding) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.g.Destroy()
+	wipe(s.buf[:])
+}
@@ -0,0 +1,150 @@
+package isaac
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingReader returns zeros and counts the bytes read.
+type countingReader struct {
+	n   int
+	err error
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	clear(p)
+	r.n += len(p)
+	return len(p), nil
+}
+
+func newTestReseeding(t *testing.T, opts ...ReseedOption) (*Reseeding, *countingReader) {
+	t.Helper()
+	g, err := NewGenerator()
+	require.NoError(t, err)
+	src := &countingReader{}
+	s, err := NewReseeding(g, append([]ReseedOption{WithReseedEntropy(src)}, opts...)...)
+	require.NoError(t, err)
+	return s, src
+}
+
+func TestReseedingWords(t *testing.T) {
+	s, src := newTestReseeding(t, WithReseedWords(10), WithReseedInterval(0))
+	ref := New[uint64]()
+	for range 10 {
+		require.Equal(t, ref.Rand(), s.Uint64())
+	}
+	require.Zero(t, s.Stats().Reseeds)
+
+	// The eleventh word comes after mixing in entropy.
+	ref.AddEntropy(make([]byte, reseedBytes))
+	for range 10 {
+		require.Equal(t, ref.Rand(), s.Uint64())
+	}
+	require.Equal(t, reseedBytes, src.n)
+
+	_, err := s.Read(make([]byte, 8*10))
+	require.NoError(t, err)
+	st := s.Stats()
+	require.Equal(t, uint64(30), st.Words)
+	require.Equal(t, uint64(2), st.Reseeds)
+	require.NoError(t, st.LastError)
+}
+
+func TestReseedingInterval(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s, _ := newTestReseeding(t, WithReseedWords(0), WithReseedInterval(time.Minute))
+	s.now = func() time.Time { return now }
+	s.last = now
+
+	s.Uint32()
+	now = now.Add(59 * time.Second)
+	s.Uint32()
+	require.Zero(t, s.Stats().Reseeds)
+	now = now.Add(time.Second)
+	s.Uint32()
+	s.Uint32()
+	st := s.Stats()
+	require.Equal(t, uint64(1), st.Reseeds)
+	require.Equal(t, now, st.LastReseed)
+}
+
+func TestReseedingFork(t *testing.T) {
+	s, _ := newTestReseeding(t)
+	s.Uint64()
+	s.pid = -1 // as if the process had forked
+	s.Uint64()
+	s.Uint64()
+	st := s.Stats()
+	require.Equal(t, uint64(1), st.Reseeds)
+	require.Equal(t, uint64(1), st.ForkReseeds)
+
+	s, _ = newTestReseeding(t, WithForkDetection(false))
+	s.pid = -1
+	s.Uint64()
+	require.Zero(t, s.Stats().Reseeds)
+}
+
+func TestReseedingForkFailure(t *testing.T) {
+	s, src := newTestReseeding(t)
+	s.Uint64()
+	src.err = errors.New("entropy source down")
+	s.pid = -1
+
+	require.PanicsWithError(t, "isaac: reseed: entropy source down", func() { s.Uint64() })
+	require.PanicsWithError(t, "isaac: reseed: entropy source down", func() { s.Uint32() })
+	_, err := s.Read(make([]byte, 8))
+	require.ErrorIs(t, err, src.err)
+	require.Equal(t, uint64(3), s.Stats().Failures)
+
+	src.err = nil
+	s.Uint64()
+	st := s.Stats()
+	require.Equal(t, uint64(1), st.ForkReseeds)
+	require.NoError(t, st.LastError)
+}
+
+func TestReseedingFailure(t *testing.T) {
+	s, src := newTestReseeding(t, WithReseedWords(1))
+	s.Uint64()
+	src.err = errors.New("entropy source down")
+
+	s.Uint64()
+	s.Uint64()
+	_, err := s.Read(make([]byte, 8))
+	require.ErrorIs(t, err, src.err)
+	st := s.Stats()
+	require.Equal(t, uint64(3), st.Failures)
+	require.ErrorIs(t, st.LastError, src.err)
+
+	src.err = nil
+	require.NoError(t, s.Reseed())
+	st = s.Stats()
+	require.Equal(t, uint64(1), st.Reseeds)
+	require.NoError(t, st.LastError)
+
+	_, err = NewReseeding(s, WithReseedInterval(-time.Second))
+	require.Error(t, err)
+}
+
+func TestReseedingDefaultSource(t *testing.T) {
+	g1, err := NewGenerator()
+	require.NoError(t, err)
+	g2, err := NewGenerator()
+	require.NoError(t, err)
+	s, err := NewReseeding(g2)
+	require.NoError(t, err)
+	require.NoError(t, s.Reseed())
+
+	b1, b2 := make([]byte, 64), make([]byte, 64)
+	_, _ = io.ReadFull(g1, b1)
+	_, _ = io.ReadFull(s, b2)
+	require.False(t, bytes.Equal(b1, b2))
+}
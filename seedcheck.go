@@ -0,0 +1,128 @@
+package isaac
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrWeakSeed is returned by CheckSeed and by generators built with
+// WithStrictSeed when a seed is weak.
+var ErrWeakSeed = errors.New("isaac: weak seed")
+
+// Thresholds of CheckSeed. Seed material read from a working entropy source
+// passes all of them with an overwhelming margin, for every word size.
+const (
+	seedMinBytes   = 16    // length of the seed material
+	seedMinSample  = Words // bytes needed for the min-entropy estimate
+	seedMinEntropy = 3     // estimated bits of min-entropy per byte
+)
+
+// publishedSeeds are the seeds of published test vectors and examples, as
+// the leading words of an otherwise zero seed table. Entries with bits set
+// apply to that word width only.
+var publishedSeeds = []struct {
+	name  string
+	bits  uint
+	words []uint64
+}{
+	{"coreutils and Jenkins test vectors", 0, nil},
+	{"Rust rand test vectors", 0, []uint64{1, 23, 456, 7890, 12345}},
+	{"WeChat ISAAC-64 write-up", 64, []uint64{0xffffffffffffffff}},
+	{"WeChat ISAAC-64 write-up", 64, []uint64{12312312}},
+}
+
+// CheckSeed reports whether seed is too weak to key a generator, returning
+// an error wrapping ErrWeakSeed that gives the reason, or nil. A seed is
+// weak when it is the seed of published test vectors, including the
+// all-zero seed New uses; when more than half of its words are zero, as for
+// short seeds padded with zeros; when fewer than half of its words are
+// distinct; or when the most common value estimate of SP 800-90B, section
+// 6.3.1, puts its min-entropy below 3 bits per byte, as for text or
+// counters.
+//
+// CheckSeed looks for mistakes, not for sources that merely look random: a
+// seed that passes is not proven to be unpredictable.
+func CheckSeed[T Word](seed [Words]T) error {
+	if err := checkPublished(&seed); err != nil {
+		return err
+	}
+	return checkMaterial(seed[:], Words*int(paramsOf[T]().bits/8))
+}
+
+// checkPublished reports seeds listed in publishedSeeds.
+func checkPublished[T Word](seed *[Words]T) error {
+	bits := paramsOf[T]().bits
+	for _, p := range publishedSeeds {
+		if p.bits != 0 && p.bits != bits {
+			continue
+		}
+		var want [Words]T
+		for i, w := range p.words {
+			want[i] = T(w)
+		}
+		if *seed == want {
+			return fmt.Errorf("%w: seed of the %s", ErrWeakSeed, p.name)
+		}
+	}
+	return nil
+}
+
+// checkMaterial checks seed material before it is padded to a table: words
+// whose little-endian encoding starts with the n bytes of material. Seeds
+// shorter than seedMinBytes are weak; the min-entropy estimate needs at
+// least seedMinSample bytes, so shorter material must instead have at least
+// n/2 distinct byte values.
+func checkMaterial[T Word](words []T, n int) error {
+	if n < seedMinBytes {
+		return fmt.Errorf("%w: %d bytes, need at least %d", ErrWeakSeed, n, seedMinBytes)
+	}
+
+	zero := 0
+	distinct := make(map[T]struct{}, len(words))
+	var counts [256]int
+	size := int(paramsOf[T]().bits / 8)
+	for i, w := range words {
+		if w == 0 {
+			zero++
+		}
+		distinct[w] = struct{}{}
+		for j := range min(size, n-i*size) {
+			counts[byte(uint64(w)>>(8*j))]++
+		}
+	}
+	if 2*zero > len(words) {
+		return fmt.Errorf("%w: %d of %d words are zero", ErrWeakSeed, zero, len(words))
+	}
+	if 2*len(distinct) < len(words) {
+		return fmt.Errorf("%w: only %d of %d words are distinct", ErrWeakSeed, len(distinct), len(words))
+	}
+	if n < seedMinSample {
+		values := 0
+		for _, c := range counts {
+			if c > 0 {
+				values++
+			}
+		}
+		if 2*values < n {
+			return fmt.Errorf("%w: only %d distinct byte values in %d bytes", ErrWeakSeed, values, n)
+		}
+		return nil
+	}
+	if h := mostCommonValue(counts[:], n); h < seedMinEntropy {
+		return fmt.Errorf("%w: estimated min-entropy %.2f bits per byte, need %d", ErrWeakSeed, h, seedMinEntropy)
+	}
+	return nil
+}
+
+// mostCommonValue returns the SP 800-90B most common value estimate of the
+// min-entropy per sample, from the counts of each value in n samples.
+func mostCommonValue(counts []int, n int) float64 {
+	most := 0
+	for _, c := range counts {
+		most = max(most, c)
+	}
+	p := float64(most) / float64(n)
+	pu := min(1, p+2.576*math.Sqrt(p*(1-p)/float64(n-1)))
+	return -math.Log2(pu)
+}
@@ -0,0 +1,128 @@
+package isaac
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// randomSeed fills a seed table from crypto/rand.
+func randomSeed[T Word](t *testing.T) [Words]T {
+	var seed [Words]T
+	buf := make([]byte, Words*8)
+	_, err := rand.Read(buf)
+	require.NoError(t, err)
+	for i := range seed {
+		seed[i] = T(binary.LittleEndian.Uint64(buf[i*8:]))
+	}
+	return seed
+}
+
+func TestCheckSeedRandom(t *testing.T) {
+	for range 100 {
+		require.NoError(t, CheckSeed(randomSeed[uint8](t)))
+		require.NoError(t, CheckSeed(randomSeed[uint16](t)))
+		require.NoError(t, CheckSeed(randomSeed[uint32](t)))
+		require.NoError(t, CheckSeed(randomSeed[uint64](t)))
+	}
+}
+
+func TestCheckSeedWeak(t *testing.T) {
+	var zero [Words]uint64
+	err := CheckSeed(zero)
+	require.ErrorIs(t, err, ErrWeakSeed)
+	require.ErrorContains(t, err, "test vectors")
+
+	mostlyZero := randomSeed[uint64](t)
+	for i := range Words/2 + 1 {
+		mostlyZero[i] = 0
+	}
+	err = CheckSeed(mostlyZero)
+	require.ErrorIs(t, err, ErrWeakSeed)
+	require.ErrorContains(t, err, "are zero")
+
+	repeated := randomSeed[uint32](t)
+	for i := 16; i < Words; i++ {
+		repeated[i] = repeated[i%16]
+	}
+	err = CheckSeed(repeated)
+	require.ErrorIs(t, err, ErrWeakSeed)
+	require.ErrorContains(t, err, "distinct")
+
+	var counter [Words]uint32
+	for i := range counter {
+		counter[i] = uint32(i + 1)
+	}
+	err = CheckSeed(counter)
+	require.ErrorIs(t, err, ErrWeakSeed)
+	require.ErrorContains(t, err, "min-entropy")
+
+	// Decimal text: distinct words, but only 11 byte values.
+	var digits []byte
+	for i := 0; len(digits) < Words*8; i++ {
+		digits = strconv.AppendInt(digits, int64(i*7919), 10)
+		digits = append(digits, ' ')
+	}
+	var text [Words]uint64
+	for i := range text {
+		text[i] = binary.LittleEndian.Uint64(digits[i*8:])
+	}
+	err = CheckSeed(text)
+	require.ErrorIs(t, err, ErrWeakSeed)
+	require.ErrorContains(t, err, "min-entropy")
+}
+
+func TestCheckSeedPublished(t *testing.T) {
+	var wx [Words]uint64
+	wx[0] = 12312312
+	err := CheckSeed(wx)
+	require.ErrorIs(t, err, ErrWeakSeed)
+	require.ErrorContains(t, err, "WeChat")
+
+	rust := [Words]uint32{1, 23, 456, 7890, 12345}
+	err = CheckSeed(rust)
+	require.ErrorIs(t, err, ErrWeakSeed)
+	require.ErrorContains(t, err, "Rust")
+}
+
+func TestWithStrictSeed(t *testing.T) {
+	_, err := NewGenerator(WithStrictSeed())
+	require.ErrorIs(t, err, ErrWeakSeed)
+
+	_, err = NewGenerator(WithStrictSeed(), WithSeed([]byte("short key")))
+	require.ErrorContains(t, err, "need at least 16")
+
+	_, err = NewGenerator(WithStrictSeed(), WithSeed([]byte("aaaaaaaaaaaaaaaaaaaabbbb")))
+	require.ErrorContains(t, err, "distinct")
+
+	_, err = NewGenerator(WithStrictSeed(), WithSeed([]uint64{12312312}), WithProfile(WeChat))
+	require.ErrorContains(t, err, "WeChat")
+
+	g, err := NewGenerator(WithStrictSeed(), WithEntropy(rand.Reader), WithWidth(32))
+	require.NoError(t, err)
+	g.Uint32()
+
+	seed := randomSeed[uint64](t)
+	_, err = NewGenerator(WithStrictSeed(), WithSeed(seed[:]))
+	require.NoError(t, err)
+	_, err = NewGenerator(WithStrictSeed(), WithSeed(seed[:4]))
+	require.NoError(t, err)
+}
+
+func TestWithStrictSeedNonce(t *testing.T) {
+	nonce := []byte("message 1")
+	for range 100 {
+		key := make([]byte, 32)
+		_, err := rand.Read(key)
+		require.NoError(t, err)
+		c, err := NewCipherWithNonce(key, nonce, WithStrictSeed())
+		require.NoError(t, err)
+		c.Destroy()
+	}
+
+	_, err := NewCipherWithNonce(make([]byte, 32), nonce, WithStrictSeed())
+	require.ErrorIs(t, err, ErrWeakSeed)
+}
@@ -0,0 +1,36 @@
+package isaac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	require.NoError(t, SelfTest())
+
+	g, err := NewGenerator(WithSelfTest())
+	require.NoError(t, err)
+	g.Uint64()
+}
+
+func TestCheckKAT(t *testing.T) {
+	s := New[uint32]()
+	require.NoError(t, checkKAT("ok", kat32[:], s.Refill))
+
+	// A kernel with a flipped output bit is caught and named.
+	broken := func(r *[Words]uint32) {
+		s.Refill(r)
+		r[7] ^= 1
+	}
+	s = New[uint32]()
+	err := checkKAT("broken", kat32[:], broken)
+	require.ErrorIs(t, err, ErrSelfTest)
+	require.ErrorContains(t, err, "broken: block 1 word 7")
+}
+
+func BenchmarkSelfTest(b *testing.B) {
+	for range b.N {
+		_ = SelfTest()
+	}
+}
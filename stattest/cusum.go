@@ -0,0 +1,37 @@
+package stattest
+
+import "math"
+
+// CumulativeSums runs the cumulative sums test, SP 800-22 §2.13, over the
+// sequence forward or, with reverse set, backward.
+func CumulativeSums(b Bits, reverse bool) (float64, error) {
+	n := len(b)
+	if n == 0 {
+		return 0, ErrTooShort
+	}
+	s, z := 0, 0
+	for i := range n {
+		x := b[i]
+		if reverse {
+			x = b[n-1-i]
+		}
+		s += 2*int(x) - 1
+		z = max(z, s, -s)
+	}
+
+	// The summation bounds use truncating integer division, as the NIST
+	// reference code does.
+	fz := float64(z)
+	sqrtN := math.Sqrt(float64(n))
+	sum1 := 0.0
+	for k := (-n/z + 1) / 4; k <= (n/z-1)/4; k++ {
+		fk := float64(k)
+		sum1 += normalCDF((4*fk+1)*fz/sqrtN) - normalCDF((4*fk-1)*fz/sqrtN)
+	}
+	sum2 := 0.0
+	for k := (-n/z - 3) / 4; k <= (n/z-1)/4; k++ {
+		fk := float64(k)
+		sum2 += normalCDF((4*fk+3)*fz/sqrtN) - normalCDF((4*fk+1)*fz/sqrtN)
+	}
+	return 1 - sum1 + sum2, nil
+}
@@ -0,0 +1,128 @@
+package stattest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Analysis holds the statistics Walker's ent program reports for a byte
+// stream.
+type Analysis struct {
+	Bytes             int64
+	Entropy           float64 // bits per byte, 8 for a perfectly random stream
+	ChiSquare         float64 // byte distribution chi-square, 255 degrees of freedom
+	ChiSquareP        float64 // probability a random stream exceeds ChiSquare
+	Mean              float64 // arithmetic mean of the bytes, 127.5 when random
+	MonteCarloPi      float64 // pi estimated from 24-bit coordinate pairs
+	SerialCorrelation float64 // correlation of each byte with the next, 0 when random
+}
+
+// monteBytes is the number of bytes ent uses for one Monte Carlo point: a
+// 24-bit x and a 24-bit y coordinate.
+const monteBytes = 6
+
+// Analyze reads r to the end and computes ent's statistics over its bytes.
+func Analyze(r io.Reader) (Analysis, error) {
+	var (
+		counts [256]int64
+		a      Analysis
+
+		monte        [monteBytes]byte
+		inCircle     int64
+		points       int64
+		first, last  float64
+		t1, t2, t3   float64
+		monteRadius2 = math.Pow(math.Pow(256, monteBytes/2)-1, 2)
+	)
+
+	br := bufio.NewReader(r)
+	for {
+		c, err := br.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return a, fmt.Errorf("stattest: reading stream: %w", err)
+		}
+
+		counts[c]++
+		u := float64(c)
+		if a.Bytes == 0 {
+			first = u
+		} else {
+			t1 += last * u
+		}
+		t2 += u
+		t3 += u * u
+		last = u
+
+		monte[a.Bytes%monteBytes] = c
+		a.Bytes++
+		if a.Bytes%monteBytes == 0 {
+			var x, y float64
+			for i := range monteBytes / 2 {
+				x = x*256 + float64(monte[i])
+				y = y*256 + float64(monte[i+monteBytes/2])
+			}
+			points++
+			if x*x+y*y <= monteRadius2 {
+				inCircle++
+			}
+		}
+	}
+	if a.Bytes == 0 {
+		return a, ErrTooShort
+	}
+
+	n := float64(a.Bytes)
+	expected := n / 256
+	sum := 0.0
+	for i, c := range counts {
+		p := float64(c) / n
+		if c > 0 {
+			a.Entropy -= p * math.Log2(p)
+		}
+		d := float64(c) - expected
+		a.ChiSquare += d * d / expected
+		sum += float64(i) * float64(c)
+	}
+	a.ChiSquareP = igamc(255.0/2, a.ChiSquare/2)
+	a.Mean = sum / n
+	if points > 0 {
+		a.MonteCarloPi = 4 * float64(inCircle) / float64(points)
+	}
+
+	// Serial correlation wraps around, pairing the last byte with the
+	// first, as ent does.
+	t1 += last * first
+	t2 *= t2
+	if d := n*t3 - t2; d != 0 {
+		a.SerialCorrelation = (n*t1 - t2) / d
+	} else {
+		a.SerialCorrelation = math.NaN()
+	}
+	return a, nil
+}
+
+// String formats the analysis the way ent prints it.
+func (a Analysis) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Entropy = %f bits per byte.\n\n", a.Entropy)
+	fmt.Fprintf(&b, "Optimum compression would reduce the size\nof this %d byte file by %d percent.\n\n",
+		a.Bytes, int(100*(8-a.Entropy)/8))
+	fmt.Fprintf(&b, "Chi square distribution for %d samples is %.2f, and randomly\n", a.Bytes, a.ChiSquare)
+	fmt.Fprintf(&b, "would exceed this value %.2f percent of the times.\n\n", 100*a.ChiSquareP)
+	fmt.Fprintf(&b, "Arithmetic mean value of data bytes is %.4f (127.5 = random).\n", a.Mean)
+	fmt.Fprintf(&b, "Monte Carlo value for Pi is %.9f (error %.2f percent).\n",
+		a.MonteCarloPi, 100*math.Abs(math.Pi-a.MonteCarloPi)/math.Pi)
+	if math.IsNaN(a.SerialCorrelation) {
+		b.WriteString("Serial correlation coefficient is undefined (all values equal!).\n")
+	} else {
+		fmt.Fprintf(&b, "Serial correlation coefficient is %f (totally uncorrelated = 0.0).\n", a.SerialCorrelation)
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package stattest
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lbbniu/isaac"
+)
+
+func TestAnalyzeUniform(t *testing.T) {
+	// Every byte value exactly 16 times: maximal entropy, zero chi-square.
+	var p []byte
+	for range 16 {
+		for i := range 256 {
+			p = append(p, byte(i))
+		}
+	}
+	a, err := Analyze(bytes.NewReader(p))
+	require.NoError(t, err)
+	require.Equal(t, int64(4096), a.Bytes)
+	require.InDelta(t, 8, a.Entropy, 1e-12)
+	require.Zero(t, a.ChiSquare)
+	require.InDelta(t, 1, a.ChiSquareP, 1e-12)
+	require.InDelta(t, 127.5, a.Mean, 1e-12)
+	require.Contains(t, a.String(), "Entropy = 8.000000 bits per byte.")
+}
+
+func TestAnalyzeConstant(t *testing.T) {
+	a, err := Analyze(strings.NewReader("aaaaaaaaaaaa"))
+	require.NoError(t, err)
+	require.Zero(t, a.Entropy)
+	require.Equal(t, float64('a'), a.Mean)
+	require.True(t, math.IsNaN(a.SerialCorrelation))
+	require.Contains(t, a.String(), "undefined")
+
+	_, err = Analyze(strings.NewReader(""))
+	require.ErrorIs(t, err, ErrTooShort)
+}
+
+func TestAnalyzeGenerator(t *testing.T) {
+	g, err := isaac.NewGenerator(isaac.WithSeed([]byte("analyze")))
+	require.NoError(t, err)
+	a, err := Analyze(io.LimitReader(g, 1<<20))
+	require.NoError(t, err)
+	require.InDelta(t, 8, a.Entropy, 0.001)
+	require.InDelta(t, 127.5, a.Mean, 0.5)
+	require.InDelta(t, math.Pi, a.MonteCarloPi, 0.02)
+	require.InDelta(t, 0, a.SerialCorrelation, 0.01)
+	require.Greater(t, a.ChiSquareP, 0.001)
+}
@@ -0,0 +1,43 @@
+package stattest
+
+import (
+	"fmt"
+	"math"
+)
+
+// Monobit runs the frequency (monobit) test, SP 800-22 §2.1: the
+// proportion of ones in the whole sequence.
+func Monobit(b Bits) (float64, error) {
+	if len(b) == 0 {
+		return 0, ErrTooShort
+	}
+	s := 0
+	for _, x := range b {
+		s += 2*int(x) - 1
+	}
+	obs := math.Abs(float64(s)) / math.Sqrt(float64(len(b)))
+	return math.Erfc(obs / math.Sqrt2), nil
+}
+
+// BlockFrequency runs the frequency test within blocks of m bits, SP
+// 800-22 §2.2. Bits past the last full block are ignored.
+func BlockFrequency(b Bits, m int) (float64, error) {
+	if m < 1 {
+		return 0, fmt.Errorf("stattest: invalid block length %d", m)
+	}
+	n := len(b) / m
+	if n == 0 {
+		return 0, ErrTooShort
+	}
+	chi2 := 0.0
+	for i := range n {
+		ones := 0
+		for _, x := range b[i*m : (i+1)*m] {
+			ones += int(x)
+		}
+		d := float64(ones)/float64(m) - 0.5
+		chi2 += d * d
+	}
+	chi2 *= 4 * float64(m)
+	return igamc(float64(n)/2, chi2/2), nil
+}
@@ -0,0 +1,78 @@
+package stattest
+
+import "math"
+
+// Runs runs the runs test, SP 800-22 §2.3: the number of uninterrupted
+// runs of identical bits. It returns 0 when the sequence fails the
+// frequency prerequisite.
+func Runs(b Bits) (float64, error) {
+	n := len(b)
+	if n < 2 {
+		return 0, ErrTooShort
+	}
+	ones := 0
+	for _, x := range b {
+		ones += int(x)
+	}
+	pi := float64(ones) / float64(n)
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(float64(n)) {
+		return 0, nil
+	}
+
+	v := 1
+	for i := 1; i < n; i++ {
+		if b[i] != b[i-1] {
+			v++
+		}
+	}
+	q := pi * (1 - pi)
+	num := math.Abs(float64(v) - 2*float64(n)*q)
+	return math.Erfc(num / (2 * math.Sqrt(2*float64(n)) * q)), nil
+}
+
+// longestRunParams are the block sizes, class bounds and class
+// probabilities of SP 800-22 §2.4, chosen by sequence length. The
+// probabilities carry the precision of the NIST reference code.
+var longestRunParams = []struct {
+	minLen int
+	m      int       // block length
+	lo, hi int       // run lengths folded into the first and last class
+	pi     []float64 // class probabilities
+}{
+	{750000, 10000, 10, 16, []float64{0.0882, 0.2092, 0.2483, 0.1933, 0.1208, 0.0675, 0.0727}},
+	{6272, 128, 4, 9, []float64{0.1174035788, 0.242955959, 0.249363483, 0.17517706, 0.102701071, 0.112398847}},
+	{128, 8, 1, 4, []float64{0.21484375, 0.3671875, 0.23046875, 0.1875}},
+}
+
+// LongestRun runs the test for the longest run of ones in a block, SP
+// 800-22 §2.4. It needs at least 128 bits.
+func LongestRun(b Bits) (float64, error) {
+	for _, p := range longestRunParams {
+		if len(b) < p.minLen {
+			continue
+		}
+		n := len(b) / p.m
+		v := make([]int, len(p.pi))
+		for i := range n {
+			longest, run := 0, 0
+			for _, x := range b[i*p.m : (i+1)*p.m] {
+				if x == 1 {
+					run++
+					longest = max(longest, run)
+				} else {
+					run = 0
+				}
+			}
+			v[min(max(longest, p.lo), p.hi)-p.lo]++
+		}
+
+		chi2 := 0.0
+		for i, pi := range p.pi {
+			e := float64(n) * pi
+			d := float64(v[i]) - e
+			chi2 += d * d / e
+		}
+		return igamc(float64(len(p.pi)-1)/2, chi2/2), nil
+	}
+	return 0, ErrTooShort
+}
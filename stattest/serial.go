@@ -0,0 +1,83 @@
+package stattest
+
+import (
+	"fmt"
+	"math"
+)
+
+// patternCounts counts the overlapping m-bit patterns of b, wrapping
+// around the end of the sequence.
+func patternCounts(b Bits, m int) []int {
+	counts := make([]int, 1<<m)
+	if m == 0 {
+		counts[0] = len(b)
+		return counts
+	}
+	mask := 1<<m - 1
+	v := 0
+	for i := range m - 1 {
+		v = v<<1 | int(b[i])
+	}
+	for i := range b {
+		v = (v<<1 | int(b[(i+m-1)%len(b)])) & mask
+		counts[v]++
+	}
+	return counts
+}
+
+// psiSquared is the ψ²_m statistic of the serial test.
+func psiSquared(b Bits, m int) float64 {
+	if m <= 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, c := range patternCounts(b, m) {
+		sum += float64(c) * float64(c)
+	}
+	n := float64(len(b))
+	return sum*float64(int(1)<<m)/n - n
+}
+
+// Serial runs the serial test, SP 800-22 §2.11: the frequency of all
+// overlapping m-bit patterns. It returns the two p-values of the test.
+func Serial(b Bits, m int) (float64, float64, error) {
+	if m < 2 || m > 24 {
+		return 0, 0, fmt.Errorf("stattest: invalid serial pattern length %d", m)
+	}
+	if len(b) < m {
+		return 0, 0, ErrTooShort
+	}
+	psi0 := psiSquared(b, m)
+	psi1 := psiSquared(b, m-1)
+	psi2 := psiSquared(b, m-2)
+	d1 := psi0 - psi1
+	d2 := psi0 - 2*psi1 + psi2
+	return igamc(math.Pow(2, float64(m-2)), d1/2), igamc(math.Pow(2, float64(m-3)), d2/2), nil
+}
+
+// phi is the φ_m statistic of the approximate entropy test.
+func phi(b Bits, m int) float64 {
+	n := float64(len(b))
+	sum := 0.0
+	for _, c := range patternCounts(b, m) {
+		if c > 0 {
+			p := float64(c) / n
+			sum += p * math.Log(p)
+		}
+	}
+	return sum
+}
+
+// ApproximateEntropy runs the approximate entropy test, SP 800-22 §2.12:
+// the frequencies of overlapping m-bit and (m+1)-bit patterns.
+func ApproximateEntropy(b Bits, m int) (float64, error) {
+	if m < 1 || m > 24 {
+		return 0, fmt.Errorf("stattest: invalid approximate entropy block length %d", m)
+	}
+	if len(b) <= m {
+		return 0, ErrTooShort
+	}
+	apen := phi(b, m) - phi(b, m+1)
+	chi2 := 2 * float64(len(b)) * (math.Ln2 - apen)
+	return igamc(math.Pow(2, float64(m-1)), chi2/2), nil
+}
@@ -0,0 +1,112 @@
+package stattest
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// Spectral runs the discrete Fourier transform (spectral) test, SP 800-22
+// §2.6: the number of peaks in the spectrum above the 95% threshold.
+func Spectral(b Bits) (float64, error) {
+	n := len(b)
+	if n < 2 {
+		return 0, ErrTooShort
+	}
+	x := make([]complex128, n)
+	for i, v := range b {
+		x[i] = complex(float64(2*int(v)-1), 0)
+	}
+	s := dft(x)
+
+	t := math.Sqrt(math.Log(1/0.05) * float64(n))
+	n0 := 0.95 * float64(n) / 2
+	n1 := 0
+	for _, v := range s[:n/2] {
+		if cmplx.Abs(v) < t {
+			n1++
+		}
+	}
+	d := (float64(n1) - n0) / math.Sqrt(float64(n)*0.95*0.05/4)
+	return math.Erfc(math.Abs(d) / math.Sqrt2), nil
+}
+
+// dft returns the discrete Fourier transform of x. Lengths that are not a
+// power of two go through Bluestein's algorithm.
+func dft(x []complex128) []complex128 {
+	n := len(x)
+	if n&(n-1) == 0 {
+		out := append([]complex128(nil), x...)
+		fft(out, false)
+		return out
+	}
+
+	// Bluestein: X_k = w_k * sum_j (x_j w_j) conj(w_{k-j}), w_k = e^(-iπk²/n),
+	// evaluated as a circular convolution of power-of-two length.
+	m := 1 << bits.Len(uint(2*n-1))
+	w := make([]complex128, n)
+	for k := range n {
+		// k² mod 2n keeps the angle small and precise.
+		// Square in uint64: k*k overflows a 32-bit int once n > 46340.
+		kk := uint64(k) * uint64(k) % uint64(2*n)
+		w[k] = cmplx.Rect(1, -math.Pi*float64(kk)/float64(n))
+	}
+	a := make([]complex128, m)
+	c := make([]complex128, m)
+	for k := range n {
+		a[k] = x[k] * w[k]
+		c[k] = cmplx.Conj(w[k])
+		if k > 0 {
+			c[m-k] = c[k]
+		}
+	}
+	fft(a, false)
+	fft(c, false)
+	for i := range a {
+		a[i] *= c[i]
+	}
+	fft(a, true)
+
+	out := make([]complex128, n)
+	for k := range n {
+		out[k] = w[k] * a[k] / complex(float64(m), 0)
+	}
+	return out
+}
+
+// fft transforms x in place with the iterative radix-2 algorithm. len(x)
+// must be a power of two. The inverse transform is not scaled.
+func fft(x []complex128, inverse bool) {
+	n := len(x)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+	sign := -1.0
+	if inverse {
+		sign = 1
+	}
+	// Twiddle factors are computed once for the full length rather than
+	// by repeated multiplication, which loses precision on long inputs.
+	twiddle := make([]complex128, n/2)
+	for k := range twiddle {
+		twiddle[k] = cmplx.Rect(1, sign*2*math.Pi*float64(k)/float64(n))
+	}
+	for size := 2; size <= n; size <<= 1 {
+		stride := n / size
+		for start := 0; start < n; start += size {
+			for k := range size / 2 {
+				u := x[start+k]
+				v := x[start+k+size/2] * twiddle[k*stride]
+				x[start+k] = u + v
+				x[start+k+size/2] = u - v
+			}
+		}
+	}
+}
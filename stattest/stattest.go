@@ -0,0 +1,155 @@
+// Package stattest implements statistical tests from NIST SP 800-22 for
+// checking the output of the generators in package isaac.
+//
+// Every test takes a bit sequence and returns one or more p-values; a
+// sequence passes at significance level alpha when all p-values are at
+// least alpha (NIST uses 0.01). NIST recommends at least 100 bits for most
+// tests and about 10^6 bits for meaningful results.
+package stattest
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+)
+
+// ErrTooShort is returned when a sequence is too short for a test or its
+// parameters.
+var ErrTooShort = errors.New("stattest: sequence too short")
+
+// Bits is a bit sequence stored one bit per element, each 0 or 1.
+type Bits []uint8
+
+// ParseBits converts a string of '0' and '1' characters to Bits, ignoring
+// any other characters.
+func ParseBits(s string) Bits {
+	var b Bits
+	for _, c := range s {
+		switch c {
+		case '0':
+			b = append(b, 0)
+		case '1':
+			b = append(b, 1)
+		}
+	}
+	return b
+}
+
+// FromBytes unpacks p into bits, most significant bit of each byte first.
+func FromBytes(p []byte) Bits {
+	b := make(Bits, 0, len(p)*8)
+	for _, c := range p {
+		for i := 7; i >= 0; i-- {
+			b = append(b, c>>i&1)
+		}
+	}
+	return b
+}
+
+// ReadBits reads n bits from r, most significant bit of each byte first.
+func ReadBits(r io.Reader, n int) (Bits, error) {
+	p := make([]byte, (n+7)/8)
+	if _, err := io.ReadFull(r, p); err != nil {
+		return nil, fmt.Errorf("stattest: reading %d bits: %w", n, err)
+	}
+	return FromBytes(p)[:n], nil
+}
+
+// Words returns a reader over the words produced by next, such as the Rand
+// method of isaac.ISAAC32, isaac.ISAAC64 or isaac.ISAAC[T]. Words are
+// written big-endian, so the bit sequence follows each word from its most
+// significant bit.
+func Words[T uint32 | uint64](next func() T) io.Reader {
+	return &wordReader[T]{next: next}
+}
+
+type wordReader[T uint32 | uint64] struct {
+	next    func() T
+	buf     [8]byte
+	pending []byte
+}
+
+func (w *wordReader[T]) Read(p []byte) (int, error) {
+	size := 8
+	if _, ok := any(T(0)).(uint32); ok {
+		size = 4
+	}
+
+	n := copy(p, w.pending)
+	w.pending = w.pending[n:]
+	for n < len(p) {
+		binary.BigEndian.PutUint64(w.buf[:], uint64(w.next())<<(64-8*size))
+		c := copy(p[n:], w.buf[:size])
+		w.pending = w.buf[c:size]
+		n += c
+	}
+	return n, nil
+}
+
+// Result is the outcome of one test. Err is set, and PValues empty, when
+// the test could not run on the sequence, for example with ErrTooShort.
+type Result struct {
+	Name    string
+	PValues []float64
+	Err     error
+}
+
+// Passed reports whether the test ran and every p-value is at least alpha.
+func (r Result) Passed(alpha float64) bool {
+	if r.Err != nil {
+		return false
+	}
+	for _, p := range r.PValues {
+		if p < alpha {
+			return false
+		}
+	}
+	return true
+}
+
+// Suite reads n bits from r and runs every test with parameters chosen
+// for that length. n must be at least 1000. Every test has a Result, in a
+// fixed order; a test that cannot run reports why in Err.
+func Suite(r io.Reader, n int) ([]Result, error) {
+	if n < 1000 {
+		return nil, fmt.Errorf("%w: suite needs at least 1000 bits, got %d", ErrTooShort, n)
+	}
+	b, err := ReadBits(r, n)
+	if err != nil {
+		return nil, err
+	}
+
+	log := bits.Len(uint(n)) - 1
+	serialM := min(16, log-3)
+	apenM := min(10, log-6)
+	blockM := max(20, n/100)
+
+	var results []Result
+	add := func(name string, err error, ps ...float64) {
+		if err != nil {
+			results = append(results, Result{Name: name, Err: err})
+			return
+		}
+		results = append(results, Result{Name: name, PValues: ps})
+	}
+	p, err := Monobit(b)
+	add("monobit", err, p)
+	p, err = BlockFrequency(b, blockM)
+	add("block-frequency", err, p)
+	p, err = Runs(b)
+	add("runs", err, p)
+	p, err = LongestRun(b)
+	add("longest-run", err, p)
+	p1, p2, err := Serial(b, serialM)
+	add("serial", err, p1, p2)
+	p, err = ApproximateEntropy(b, apenM)
+	add("approximate-entropy", err, p)
+	p1, err = CumulativeSums(b, false)
+	p2, err2 := CumulativeSums(b, true)
+	add("cumulative-sums", errors.Join(err, err2), p1, p2)
+	p, err = Spectral(b)
+	add("spectral", err, p)
+	return results, nil
+}
@@ -0,0 +1,166 @@
+package stattest
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/lbbniu/isaac"
+)
+
+// epsilon100 is the 100-bit example sequence of SP 800-22 (the first bits
+// of the binary expansion of pi).
+const epsilon100 = "1100100100001111110110101010001000100001011010001100001000110100" +
+	"110001001100011001100010100010111000"
+
+// TestExamples checks the tests against the worked examples of SP 800-22.
+func TestExamples(t *testing.T) {
+	eps := ParseBits(epsilon100)
+	require.Len(t, eps, 100)
+
+	check := func(want float64) func(float64, error) {
+		return func(got float64, err error) {
+			t.Helper()
+			require.NoError(t, err)
+			require.InDelta(t, want, got, 1e-6)
+		}
+	}
+
+	check(0.527089)(Monobit(ParseBits("1011010101")))
+	check(0.109599)(Monobit(eps))
+	check(0.801252)(BlockFrequency(ParseBits("0110011010"), 3))
+	check(0.706438)(BlockFrequency(eps, 10))
+	check(0.147232)(Runs(ParseBits("1001101011")))
+	check(0.500798)(Runs(eps))
+	check(0.180609)(LongestRun(ParseBits("11001100000101010110110001001100111000000000001001" +
+		"00110101010001000100111101011010000000110101111100" +
+		"1100111001101101100010110010")))
+	check(0.261961)(ApproximateEntropy(ParseBits("0100110101"), 3))
+	check(0.235301)(ApproximateEntropy(eps, 2))
+	check(0.4116588)(CumulativeSums(ParseBits("1011010111"), false))
+	check(0.219194)(CumulativeSums(eps, false))
+	check(0.114866)(CumulativeSums(eps, true))
+	check(0.646355)(Spectral(eps))
+
+	p1, p2, err := Serial(ParseBits("0011011101"), 3)
+	require.NoError(t, err)
+	require.InDelta(t, 0.808792, p1, 1e-6)
+	require.InDelta(t, 0.670320, p2, 1e-6)
+}
+
+// TestDFT checks Bluestein's algorithm against the power-of-two FFT.
+func TestDFT(t *testing.T) {
+	x := make([]complex128, 100)
+	for i := range x {
+		x[i] = complex(float64(i%7)-3, 0)
+	}
+	got := dft(x)
+	for k := range x {
+		var want complex128
+		for j, v := range x {
+			angle := -2 * math.Pi * float64(j*k%len(x)) / float64(len(x))
+			want += v * cmplx.Rect(1, angle)
+		}
+		require.InDelta(t, real(want), real(got[k]), 1e-9)
+		require.InDelta(t, imag(want), imag(got[k]), 1e-9)
+	}
+}
+
+// TestDFTLarge checks Bluestein's chirp past the length where k*k
+// overflows a 32-bit int.
+func TestDFTLarge(t *testing.T) {
+	x := make([]complex128, 50_001)
+	for i := range x {
+		x[i] = complex(float64(i%7)-3, 0)
+	}
+	got := dft(x)
+	for _, k := range []int{1, 46_341, 50_000} {
+		var want complex128
+		for j, v := range x {
+			angle := -2 * math.Pi * float64(uint64(j)*uint64(k)%uint64(len(x))) / float64(len(x))
+			want += v * cmplx.Rect(1, angle)
+		}
+		require.InDelta(t, real(want), real(got[k]), 1e-6)
+		require.InDelta(t, imag(want), imag(got[k]), 1e-6)
+	}
+}
+
+func TestShortInput(t *testing.T) {
+	_, err := LongestRun(ParseBits("0101"))
+	require.ErrorIs(t, err, ErrTooShort)
+	_, err = Suite(bytes.NewReader(make([]byte, 10)), 80)
+	require.ErrorIs(t, err, ErrTooShort)
+	_, err = Suite(bytes.NewReader(make([]byte, 10)), 1000)
+	require.Error(t, err)
+
+	results, err := Suite(bytes.NewReader(make([]byte, 125)), 1000)
+	require.NoError(t, err)
+	require.Len(t, results, 8)
+
+	skipped := Result{Name: "longest-run", Err: ErrTooShort}
+	require.False(t, skipped.Passed(alpha))
+}
+
+// alpha is the significance level of the quality gates. It is lower than
+// the NIST default so a deterministic false positive across the many seeds
+// below stays unlikely.
+const alpha = 0.0001
+
+// suiteBits is the sequence length of the quality gates.
+const suiteBits = 1 << 17
+
+func checkSuite(t *testing.T, name string, r interface{ Read([]byte) (int, error) }) {
+	t.Helper()
+	results, err := Suite(r, suiteBits)
+	require.NoError(t, err)
+	require.Len(t, results, 8)
+	for _, res := range results {
+		require.True(t, res.Passed(alpha), "%s: %s p-values %v", name, res.Name, res.PValues)
+	}
+}
+
+// TestGenerators runs the suite over every generator for several seeds.
+func TestGenerators(t *testing.T) {
+	for seed := range uint64(8) {
+		t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+			s32 := isaac.New32()
+			s32.Seed([isaac.Words]uint32{uint32(seed)})
+			checkSuite(t, "ISAAC32", Words(s32.Rand))
+
+			s64 := isaac.New64()
+			s64.Seed([isaac.Words]uint64{seed})
+			checkSuite(t, "ISAAC64", Words(s64.Rand))
+
+			g32 := isaac.New[uint32]()
+			g32.Seed([isaac.Words]uint32{uint32(seed)})
+			checkSuite(t, "ISAAC[uint32]", Words(g32.Rand))
+
+			g64 := isaac.New[uint64]()
+			g64.Seed([isaac.Words]uint64{seed})
+			checkSuite(t, "ISAAC[uint64]", Words(g64.Rand))
+
+			for _, p := range isaac.Profiles() {
+				g, err := isaac.NewGenerator(isaac.WithSeed([]uint64{seed}), isaac.WithProfile(p))
+				require.NoError(t, err)
+				checkSuite(t, p.String(), g)
+			}
+		})
+	}
+}
+
+// TestBadStream checks that the gates reject a degenerate stream.
+func TestBadStream(t *testing.T) {
+	results, err := Suite(bytes.NewReader(bytes.Repeat([]byte{0x0f}, suiteBits/8)), suiteBits)
+	require.NoError(t, err)
+	failed := 0
+	for _, res := range results {
+		if !res.Passed(alpha) {
+			failed++
+		}
+	}
+	require.Greater(t, failed, 0)
+}
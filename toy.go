@@ -0,0 +1,104 @@
+package isaac
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Toy is a reduced-size ISAAC instance: the same kernel as ISAAC[T], run
+// over a state table of 1<<log words instead of Words. Combined with the
+// 8-bit and 16-bit word types it gives states small enough to study
+// cycle structure and bad-state classes. Toy instances are not
+// cryptographically secure and are not safe for concurrent use.
+//
+// The second state lookup of each step indexes the table with the word
+// shifted right by log, so the table may hold at most 1<<((bits-shift)/2)
+// words, where shift is log2 of the word size in bytes: 16 words for
+// uint8 and 128 for uint16. Larger tables would read only part of the
+// state and no longer be a scaled-down ISAAC.
+type Toy[T Word] struct {
+	m         []T // state table
+	a         T
+	b         T
+	c         T
+	destroyed bool
+}
+
+// NewToy creates a toy instance with a state table of 1<<log words, seeded
+// with zeros. log must be at least 3 and at most 4 for uint8 words, 7 for
+// uint16 words and WordsLog for the wider ones.
+func NewToy[T Word](log int) *Toy[T] {
+	if hi := maxToyLog[T](); log < 3 || log > hi {
+		panic(fmt.Sprintf("isaac: toy state size log must be between 3 and %d", hi))
+	}
+	s := &Toy[T]{m: make([]T, 1<<log)}
+	s.Seed(make([]T, 1<<log))
+	return s
+}
+
+// maxToyLog returns the largest table size log for which both state
+// lookups of a step see every word of the table.
+func maxToyLog[T Word]() int {
+	p := paramsOf[T]()
+	return min(WordsLog, int(p.bits-p.shift)/2)
+}
+
+// Size returns the number of words in the state table.
+func (s *Toy[T]) Size() int {
+	return len(s.m)
+}
+
+// Seed initializes the toy instance. seed must hold exactly Size words.
+func (s *Toy[T]) Seed(seed []T, initValues ...T) {
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
+	if len(seed) != len(s.m) {
+		panic(fmt.Sprintf("isaac: need exactly %d seed words", len(s.m)))
+	}
+	if len(initValues) > 0 && len(initValues) != 8 {
+		panic("isaac: need exactly 8 initial values")
+	}
+
+	v := InitValues[T]()
+	if len(initValues) == 8 {
+		copy(v[:], initValues)
+	}
+
+	copy(s.m, seed)
+	seedState(s.m, v)
+
+	s.a = 0
+	s.b = 0
+	s.c = 0
+}
+
+// Refill replenishes the random number array. r must hold at least Size
+// words; only the first Size are written.
+func (s *Toy[T]) Refill(r []T) {
+	if s.destroyed {
+		panic(ErrDestroyed)
+	}
+	s.a, s.b, s.c = refill(s.m, r[:len(s.m)], s.a, s.b, s.c)
+}
+
+// Destroy zeroes the state table and the registers. Toy instances are not
+// secure, but a toy keyed from real material should not leave it behind
+// either. Any later call to Seed or Refill panics with ErrDestroyed.
+func (s *Toy[T]) Destroy() {
+	wipe(s.m)
+	s.a, s.b, s.c = 0, 0, 0
+	s.destroyed = true
+}
+
+// Clone returns an independent copy of the current state.
+func (s *Toy[T]) Clone() *Toy[T] {
+	c := *s
+	c.m = slices.Clone(s.m)
+	return &c
+}
+
+// Equal reports whether s and o are in the same state.
+func (s *Toy[T]) Equal(o *Toy[T]) bool {
+	return s.a == o.a && s.b == o.b && s.c == o.c && slices.Equal(s.m, o.m)
+}
@@ -0,0 +1,49 @@
+package isaac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestToyFullSize checks that a toy instance of full size runs the same
+// kernel as ISAAC[T].
+func TestToyFullSize(t *testing.T) {
+	s := New[uint32]()
+	toy := NewToy[uint32](WordsLog)
+	var r [Words]uint32
+	toyR := make([]uint32, Words)
+	for range 3 {
+		s.Refill(&r)
+		toy.Refill(toyR)
+		require.Equal(t, r[:], toyR)
+	}
+}
+
+func TestToyWidths(t *testing.T) {
+	s8 := NewToy[uint8](4)
+	r8 := make([]uint8, s8.Size())
+	s8.Refill(r8)
+	require.Len(t, r8, 16)
+
+	s16 := New[uint16]()
+	first := s16.Rand()
+	s16.Seed([Words]uint16{})
+	require.Equal(t, first, s16.Rand())
+
+	require.Panics(t, func() { NewToy[uint8](2) })
+	require.Panics(t, func() { NewToy[uint8](5) })
+	require.Panics(t, func() { NewToy[uint16](8) })
+	require.NotPanics(t, func() { NewToy[uint16](7) })
+	require.Panics(t, func() { NewToy[uint8](3).Seed(make([]uint8, 4)) })
+}
+
+func TestToyClone(t *testing.T) {
+	s := NewToy[uint16](5)
+	c := s.Clone()
+	require.True(t, s.Equal(c))
+
+	r := make([]uint16, s.Size())
+	c.Refill(r)
+	require.False(t, s.Equal(c))
+}
@@ -0,0 +1,89 @@
+package isaac
+
+import (
+	"bytes"
+	"errors"
+)
+
+// ErrKeyMismatch is returned by VerifyKey and DetectFormat when no signature
+// matches the decrypted prefix.
+var ErrKeyMismatch = errors.New("isaac: no known file signature, wrong decode key?")
+
+// Magic is a byte string expected at an offset of a file.
+type Magic struct {
+	Offset int
+	Bytes  []byte
+}
+
+// Signature identifies a file format by the magic bytes that all appear at
+// their offsets.
+type Signature struct {
+	Format string
+	Magic  []Magic
+}
+
+// match reports whether every magic of s appears in p.
+func (s Signature) match(p []byte) bool {
+	for _, m := range s.Magic {
+		end := m.Offset + len(m.Bytes)
+		if end > len(p) || !bytes.Equal(p[m.Offset:end], m.Bytes) {
+			return false
+		}
+	}
+	return len(s.Magic) > 0
+}
+
+// size returns the number of leading bytes s needs.
+func (s Signature) size() int {
+	n := 0
+	for _, m := range s.Magic {
+		n = max(n, m.Offset+len(m.Bytes))
+	}
+	return n
+}
+
+// DefaultSignatures are the formats WeChat media usually decrypts to.
+var DefaultSignatures = []Signature{
+	{Format: "mp4", Magic: []Magic{{Offset: 4, Bytes: []byte("ftyp")}}},
+	{Format: "jpeg", Magic: []Magic{{Offset: 0, Bytes: []byte{0xff, 0xd8, 0xff}}}},
+	{Format: "png", Magic: []Magic{{Offset: 0, Bytes: []byte("\x89PNG\r\n\x1a\n")}}},
+	{Format: "gif", Magic: []Magic{{Offset: 0, Bytes: []byte("GIF8")}}},
+	{Format: "webp", Magic: []Magic{{Offset: 0, Bytes: []byte("RIFF")}, {Offset: 8, Bytes: []byte("WEBP")}}},
+}
+
+// VerifyKey decrypts the start of a WeChat media file with the wxisaac64
+// keystream of key and returns the format of the first signature that
+// matches, so tooling can reject a wrong decode key before writing any
+// output. Without signatures DefaultSignatures are used. ciphertextPrefix
+// is not modified.
+func VerifyKey(ciphertextPrefix []byte, key uint64, signatures ...Signature) (string, error) {
+	if len(signatures) == 0 {
+		signatures = DefaultSignatures
+	}
+	n := 0
+	for _, s := range signatures {
+		n = max(n, s.size())
+	}
+	n = min(n, len(ciphertextPrefix))
+
+	ks := NewWxKeystream(key)
+	defer ks.Destroy()
+	plain := make([]byte, n)
+	ks.XORKeyStream(plain, ciphertextPrefix[:n])
+	return DetectFormat(plain, signatures...)
+}
+
+// DetectFormat returns the format of the first signature matching the start
+// of an already decrypted file, or ErrKeyMismatch. Without signatures
+// DefaultSignatures are used.
+func DetectFormat(plain []byte, signatures ...Signature) (string, error) {
+	if len(signatures) == 0 {
+		signatures = DefaultSignatures
+	}
+	for _, s := range signatures {
+		if s.match(plain) {
+			return s.Format, nil
+		}
+	}
+	return "", ErrKeyMismatch
+}
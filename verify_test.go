@@ -0,0 +1,61 @@
+package isaac
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyKey(t *testing.T) {
+	const key = 2914389210
+	for format, header := range map[string][]byte{
+		"mp4":  []byte("\x00\x00\x00\x20ftypisom\x00\x00\x02\x00"),
+		"jpeg": {0xff, 0xd8, 0xff, 0xe0, 0x00, 0x10, 'J', 'F', 'I', 'F'},
+		"png":  []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+		"gif":  []byte("GIF89a\x01\x00\x01\x00"),
+		"webp": []byte("RIFF\x24\x00\x00\x00WEBPVP8 "),
+	} {
+		ct := append([]byte{}, header...)
+		NewWxKeystream(key).XORKeyStream(ct, ct)
+		orig := append([]byte{}, ct...)
+
+		got, err := VerifyKey(ct, key)
+		require.NoError(t, err, format)
+		require.Equal(t, format, got)
+		require.Equal(t, orig, ct)
+
+		_, err = VerifyKey(ct, key+1)
+		require.ErrorIs(t, err, ErrKeyMismatch, format)
+	}
+}
+
+func TestVerifyKeyCustom(t *testing.T) {
+	const key = 42
+	ct := []byte("%PDF-1.7")
+	NewWxKeystream(key).XORKeyStream(ct, ct)
+
+	_, err := VerifyKey(ct, key)
+	require.ErrorIs(t, err, ErrKeyMismatch)
+
+	pdf := Signature{Format: "pdf", Magic: []Magic{{Offset: 0, Bytes: []byte("%PDF-")}}}
+	got, err := VerifyKey(ct, key, pdf)
+	require.NoError(t, err)
+	require.Equal(t, "pdf", got)
+
+	// Too short for the signature.
+	_, err = VerifyKey(ct[:3], key, pdf)
+	require.ErrorIs(t, err, ErrKeyMismatch)
+	_, err = VerifyKey(ct, key, Signature{Format: "empty"})
+	require.ErrorIs(t, err, ErrKeyMismatch)
+}
+
+func TestDetectFormat(t *testing.T) {
+	got, err := DetectFormat([]byte("GIF87a"))
+	require.NoError(t, err)
+	require.Equal(t, "gif", got)
+
+	_, err = DetectFormat([]byte("RIFF\x00\x00\x00\x00WAVE"))
+	require.ErrorIs(t, err, ErrKeyMismatch)
+	_, err = DetectFormat(nil)
+	require.ErrorIs(t, err, ErrKeyMismatch)
+}
@@ -0,0 +1,133 @@
+package isaac
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"io"
+)
+
+// WxPrefixLen is the length of the encrypted prefix of WeChat channel
+// videos.
+const WxPrefixLen = 131072
+
+// WxKeystream generates the wxisaac64 keystream of WeChat media: ISAAC64
+// seeded with the decode key as its first seed word, with every result
+// block written from the last word to the first, each word big-endian (see
+// TestWxIsaac64). It owns its ISAAC64 and is not safe for concurrent use.
+type WxKeystream struct {
+	s         ISAAC64
+	block     [Words]uint64
+	buf       [Words * 8]byte
+	off       int // read position in buf
+	destroyed bool
+}
+
+var _ cipher.Stream = (*WxKeystream)(nil)
+
+// NewWxKeystream returns the keystream for a decode key.
+func NewWxKeystream(key uint64) *WxKeystream {
+	k := &WxKeystream{}
+	k.Reset(key)
+	return k
+}
+
+// Reset restarts the keystream for another decode key, reusing the state.
+func (k *WxKeystream) Reset(key uint64) {
+	var seed [Words]uint64
+	seed[0] = key
+	k.s.Seed(seed)
+	k.off = len(k.buf)
+}
+
+// XORKeyStream implements cipher.Stream.
+func (k *WxKeystream) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("isaac: output smaller than input")
+	}
+	if k.destroyed {
+		panic(ErrDestroyed)
+	}
+	for i := range src {
+		if k.off == len(k.buf) {
+			k.refill()
+		}
+		dst[i] = src[i] ^ k.buf[k.off]
+		k.off++
+	}
+}
+
+// Destroy zeroes the keystream state and buffers. Reset and XORKeyStream
+// panic with ErrDestroyed afterwards.
+func (k *WxKeystream) Destroy() {
+	k.s.Destroy()
+	wipe(k.block[:])
+	wipe(k.buf[:])
+	k.off = len(k.buf)
+	k.destroyed = true
+}
+
+// refill serializes the next result block in the reversed big-endian layout.
+func (k *WxKeystream) refill() {
+	k.s.Refill(&k.block)
+	for i, w := range k.block {
+		binary.BigEndian.PutUint64(k.buf[(Words-1-i)*8:], w)
+	}
+	k.off = 0
+}
+
+// NewWxDecryptReader returns a reader that decrypts the first prefixLen
+// bytes of r with the keystream of key and passes the rest through, as
+// WeChat encrypts only a prefix of channel videos (usually WxPrefixLen).
+func NewWxDecryptReader(r io.Reader, key uint64, prefixLen int) io.Reader {
+	return &wxReader{r: r, ks: NewWxKeystream(key), left: prefixLen}
+}
+
+type wxReader struct {
+	r    io.Reader
+	ks   *WxKeystream
+	left int // prefix bytes still to decrypt
+}
+
+func (x *wxReader) Read(p []byte) (int, error) {
+	n, err := x.r.Read(p)
+	if c := min(n, x.left); c > 0 {
+		x.ks.XORKeyStream(p[:c], p[:c])
+		x.left -= c
+	}
+	return n, err
+}
+
+// NewWxEncryptWriter returns a writer that encrypts the first prefixLen
+// bytes written to it with the keystream of key and passes the rest
+// through to w, producing files NewWxDecryptReader reads back.
+func NewWxEncryptWriter(w io.Writer, key uint64, prefixLen int) io.Writer {
+	return &wxWriter{w: w, ks: NewWxKeystream(key), left: prefixLen}
+}
+
+type wxWriter struct {
+	w    io.Writer
+	ks   *WxKeystream
+	left int // prefix bytes still to encrypt
+	buf  []byte
+}
+
+func (x *wxWriter) Write(p []byte) (int, error) {
+	written := 0
+	if c := min(len(p), x.left); c > 0 {
+		// Encrypt into a scratch buffer: Write must not modify p.
+		x.buf = append(x.buf[:0], p[:c]...)
+		x.ks.XORKeyStream(x.buf, x.buf)
+		n, err := x.w.Write(x.buf)
+		x.left -= n
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[c:]
+	}
+	if len(p) == 0 {
+		return written, nil
+	}
+	n, err := x.w.Write(p)
+	return written + n, err
+}
@@ -0,0 +1,62 @@
+package isaac
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestWxKeystream checks the keystream against the WeChat generator
+// profile across several result blocks.
+func TestWxKeystream(t *testing.T) {
+	for _, key := range []uint64{0xffffffffffffffff, 12312312} {
+		g, err := NewGenerator(WithSeed([]uint64{key}), WithProfile(WeChat))
+		require.NoError(t, err)
+		want := make([]byte, 3*Words*8+5)
+		_, _ = g.Read(want)
+
+		ks := NewWxKeystream(0)
+		ks.Reset(key)
+		got := make([]byte, len(want))
+		ks.XORKeyStream(got[:100], got[:100])
+		ks.XORKeyStream(got[100:], got[100:])
+		require.Equal(t, want, got)
+	}
+}
+
+func TestWxDecryptReader(t *testing.T) {
+	const key, prefix = 1234567890, 5000
+	plain := bytes.Repeat([]byte("ftyp video data "), 1000)
+
+	var enc bytes.Buffer
+	w := NewWxEncryptWriter(&enc, key, prefix)
+	for p := plain; len(p) > 0; {
+		n := min(len(p), 777)
+		_, err := w.Write(p[:n])
+		require.NoError(t, err)
+		p = p[n:]
+	}
+	require.Equal(t, plain[prefix:], enc.Bytes()[prefix:])
+	require.NotEqual(t, plain[:prefix], enc.Bytes()[:prefix])
+
+	ks := NewWxKeystream(key)
+	want := append([]byte{}, plain[:prefix]...)
+	ks.XORKeyStream(want, want)
+	require.Equal(t, want, enc.Bytes()[:prefix])
+
+	got, err := io.ReadAll(iotest.OneByteReader(NewWxDecryptReader(bytes.NewReader(enc.Bytes()), key, prefix)))
+	require.NoError(t, err)
+	require.Equal(t, plain, got)
+
+	// A prefix longer than the data encrypts everything.
+	short := []byte("tiny")
+	enc.Reset()
+	_, err = NewWxEncryptWriter(&enc, key, prefix).Write(short)
+	require.NoError(t, err)
+	got, err = io.ReadAll(NewWxDecryptReader(&enc, key, prefix))
+	require.NoError(t, err)
+	require.Equal(t, short, got)
+}
@@ -0,0 +1,19 @@
+package isaac
+
+import (
+	"errors"
+	"runtime"
+)
+
+// ErrDestroyed is returned, or used as the panic value, when a generator is
+// used after Destroy.
+var ErrDestroyed = errors.New("isaac: use of destroyed generator")
+
+// wipe zeroes p. It is kept out of line and keeps p alive past the stores,
+// so the compiler cannot drop them as dead even when p is never read again.
+//
+//go:noinline
+func wipe[T Word](p []T) {
+	clear(p)
+	runtime.KeepAlive(p)
+}
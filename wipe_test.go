@@ -0,0 +1,89 @@
+package isaac
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestroy(t *testing.T) {
+	s := New[uint64]()
+	s.Rand()
+	s.Destroy()
+	require.Equal(t, [Words]uint64{}, s.m)
+	require.Equal(t, [Words]uint64{}, s.r)
+	require.Zero(t, s.a|s.b|s.c)
+	require.PanicsWithValue(t, ErrDestroyed, func() { s.Rand() })
+	require.PanicsWithValue(t, ErrDestroyed, func() { s.Refill(&[Words]uint64{}) })
+	require.PanicsWithValue(t, ErrDestroyed, func() { s.Seed([Words]uint64{1}) })
+
+	s32 := New32()
+	s32.Rand()
+	s32.Destroy()
+	require.Equal(t, [Words]uint32{}, s32.m)
+	require.Equal(t, [Words]uint32{}, s32.r)
+	require.Zero(t, s32.a|s32.b|s32.c)
+	require.PanicsWithValue(t, ErrDestroyed, func() { s32.Rand() })
+	require.PanicsWithValue(t, ErrDestroyed, func() { s32.Refill(&[Words]uint32{}) })
+
+	s64 := New64()
+	s64.Rand()
+	s64.Destroy()
+	require.Equal(t, [Words]uint64{}, s64.m)
+	require.Equal(t, [Words]uint64{}, s64.r)
+	require.Zero(t, s64.a|s64.b|s64.c)
+	require.PanicsWithValue(t, ErrDestroyed, func() { s64.Rand() })
+	require.PanicsWithValue(t, ErrDestroyed, func() { s64.Seed([Words]uint64{}) })
+
+	toy := NewToy[uint16](4)
+	toy.Refill(make([]uint16, toy.Size()))
+	toy.Destroy()
+	require.Equal(t, make([]uint16, 16), toy.m)
+	require.Zero(t, toy.a|toy.b|toy.c)
+	require.PanicsWithValue(t, ErrDestroyed, func() { toy.Refill(make([]uint16, 16)) })
+	require.PanicsWithValue(t, ErrDestroyed, func() { toy.Seed(make([]uint16, 16)) })
+}
+
+func TestDestroyGenerator(t *testing.T) {
+	for _, width := range []int{32, 64} {
+		g, err := NewGenerator(WithWidth(width), WithSeed([]byte("key")))
+		require.NoError(t, err)
+		_, err = g.Read(make([]byte, 3))
+		require.NoError(t, err)
+		g.Destroy()
+
+		n, err := g.Read(make([]byte, 8))
+		require.ErrorIs(t, err, ErrDestroyed)
+		require.Zero(t, n)
+		require.PanicsWithValue(t, ErrDestroyed, func() { g.Uint32() })
+		require.PanicsWithValue(t, ErrDestroyed, func() { g.Uint64() })
+	}
+
+	c, err := NewCipher([]byte("key"))
+	require.NoError(t, err)
+	c.XORKeyStream(make([]byte, 10), make([]byte, 10))
+	c.Destroy()
+	require.Equal(t, [1024]byte{}, c.buf)
+	require.PanicsWithValue(t, ErrDestroyed, func() { c.XORKeyStream(make([]byte, 1), make([]byte, 1)) })
+}
+
+func TestDestroyWxKeystream(t *testing.T) {
+	k := NewWxKeystream(1)
+	k.XORKeyStream(make([]byte, 10), make([]byte, 10))
+	k.Destroy()
+	require.Equal(t, [Words * 8]byte{}, k.buf)
+	require.Equal(t, [Words]uint64{}, k.s.m)
+	require.PanicsWithValue(t, ErrDestroyed, func() { k.XORKeyStream(make([]byte, 1), make([]byte, 1)) })
+	require.PanicsWithValue(t, ErrDestroyed, func() { k.Reset(1) })
+}
+
+func TestDestroyReaderAt(t *testing.T) {
+	d, err := NewDecryptReaderAt(bytes.NewReader(make([]byte, 100)), []byte("key"))
+	require.NoError(t, err)
+	_, err = d.ReadAt(make([]byte, 10), 50)
+	require.NoError(t, err)
+	d.Destroy()
+	_, err = d.ReadAt(make([]byte, 10), 50)
+	require.ErrorIs(t, err, ErrDestroyed)
+}